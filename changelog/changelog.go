@@ -0,0 +1,240 @@
+package changelog
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/raid-codex/tools/common"
+	"github.com/raid-codex/tools/snapshot"
+)
+
+// Changelog lists what changed on champions between two versions of the data directory
+type Changelog struct {
+	From string
+	To   string
+	// Date is the date of the To version, today for the current version
+	Date             time.Time
+	NewChampions     common.ChampionList
+	RemovedChampions common.ChampionList
+	Changes          []*ChampionChanges
+}
+
+// ChampionChanges gathers the changes of a champion present in both versions
+type ChampionChanges struct {
+	Champion *common.Champion
+	Skills   []*SkillChange
+	Ratings  []*RatingMove
+	Builds   []*common.Build
+	Videos   []*common.Video
+}
+
+const (
+	SkillChange_Added   = "added"
+	SkillChange_Removed = "removed"
+	SkillChange_Changed = "changed"
+)
+
+// SkillChange describes a skill that was added, removed or changed, Fields lists what changed
+type SkillChange struct {
+	Name   string
+	Change string
+	Fields []string
+}
+
+// RatingMove is a grade that changed in a location, From or To are empty when there was no grade
+type RatingMove struct {
+	Location string
+	From     string
+	To       string
+}
+
+// Up returns true when the champion got a better grade
+func (rm *RatingMove) Up() bool {
+	return gradeRank(rm.To) > gradeRank(rm.From)
+}
+
+func gradeRank(grade string) int {
+	for idx, g := range common.RatingGrades {
+		if g == grade {
+			return idx
+		}
+	}
+	return 0
+}
+
+// Load reads two versions of the data directory and compares them
+func Load(dataDirectory, from, to string) (*Changelog, error) {
+	oldChampions, errOld := snapshot.Champions(dataDirectory, from)
+	if errOld != nil {
+		return nil, errOld
+	}
+	newChampions, errNew := snapshot.Champions(dataDirectory, to)
+	if errNew != nil {
+		return nil, errNew
+	}
+	return Diff(from, to, oldChampions, newChampions), nil
+}
+
+// Diff compares two lists of champions
+func Diff(from, to string, oldChampions, newChampions common.ChampionList) *Changelog {
+	date, errDate := time.Parse(snapshot.VersionFormat, to)
+	if errDate != nil {
+		date = time.Now()
+	}
+	changelog := &Changelog{
+		From:             from,
+		To:               to,
+		Date:             date,
+		NewChampions:     make(common.ChampionList, 0),
+		RemovedChampions: make(common.ChampionList, 0),
+		Changes:          make([]*ChampionChanges, 0),
+	}
+	oldBySlug := map[string]*common.Champion{}
+	for _, champion := range oldChampions {
+		oldBySlug[champion.Slug] = champion
+	}
+	for _, champion := range newChampions {
+		old, ok := oldBySlug[champion.Slug]
+		if !ok {
+			changelog.NewChampions = append(changelog.NewChampions, champion)
+			continue
+		}
+		delete(oldBySlug, champion.Slug)
+		if changes := diffChampion(old, champion); changes != nil {
+			changelog.Changes = append(changelog.Changes, changes)
+		}
+	}
+	for _, champion := range oldBySlug {
+		changelog.RemovedChampions = append(changelog.RemovedChampions, champion)
+	}
+	for _, list := range []common.ChampionList{changelog.NewChampions, changelog.RemovedChampions} {
+		sort.SliceStable(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	}
+	sort.SliceStable(changelog.Changes, func(i, j int) bool {
+		return changelog.Changes[i].Champion.Name < changelog.Changes[j].Champion.Name
+	})
+	return changelog
+}
+
+// Empty returns true when nothing changed
+func (c *Changelog) Empty() bool {
+	return len(c.NewChampions) == 0 && len(c.RemovedChampions) == 0 && len(c.Changes) == 0
+}
+
+func diffChampion(old, champion *common.Champion) *ChampionChanges {
+	changes := &ChampionChanges{
+		Champion: champion,
+		Skills:   diffSkills(old.Skills, champion.Skills),
+		Ratings:  diffRatings(old.Rating, champion.Rating),
+		Builds:   make([]*common.Build, 0),
+		Videos:   make([]*common.Video, 0),
+	}
+	oldBuilds := map[string]bool{}
+	for _, build := range old.RecommendedBuilds {
+		oldBuilds[jsonKey(build)] = true
+	}
+	for _, build := range champion.RecommendedBuilds {
+		if !oldBuilds[jsonKey(build)] {
+			changes.Builds = append(changes.Builds, build)
+		}
+	}
+	oldVideos := map[string]bool{}
+	for _, video := range old.Videos {
+		oldVideos[video.Source+"/"+video.ID] = true
+	}
+	for _, video := range champion.Videos {
+		if !oldVideos[video.Source+"/"+video.ID] {
+			changes.Videos = append(changes.Videos, video)
+		}
+	}
+	if len(changes.Skills) == 0 && len(changes.Ratings) == 0 && len(changes.Builds) == 0 && len(changes.Videos) == 0 {
+		return nil
+	}
+	return changes
+}
+
+func diffSkills(oldSkills, skills []*common.Skill) []*SkillChange {
+	changes := make([]*SkillChange, 0)
+	oldBySlug := map[string]*common.Skill{}
+	for _, skill := range oldSkills {
+		oldBySlug[skill.Slug] = skill
+	}
+	for _, skill := range skills {
+		old, ok := oldBySlug[skill.Slug]
+		if !ok {
+			changes = append(changes, &SkillChange{Name: skill.Name, Change: SkillChange_Added})
+			continue
+		}
+		delete(oldBySlug, skill.Slug)
+		fields := make([]string, 0)
+		if old.RawDescription != skill.RawDescription {
+			fields = append(fields, "description")
+		}
+		if old.Cooldown != skill.Cooldown {
+			fields = append(fields, "cooldown")
+		}
+		if old.Passive != skill.Passive {
+			fields = append(fields, "passive")
+		}
+		if jsonKey(old.Upgrades) != jsonKey(skill.Upgrades) {
+			fields = append(fields, "upgrades")
+		}
+		if len(fields) > 0 {
+			changes = append(changes, &SkillChange{Name: skill.Name, Change: SkillChange_Changed, Fields: fields})
+		}
+	}
+	for _, skill := range oldSkills {
+		if _, ok := oldBySlug[skill.Slug]; ok {
+			changes = append(changes, &SkillChange{Name: skill.Name, Change: SkillChange_Removed})
+		}
+	}
+	return changes
+}
+
+// diffRatings compares grades location by location, locations follow the json names of Rating
+func diffRatings(old, rating *common.Rating) []*RatingMove {
+	if old == nil {
+		old = &common.Rating{}
+	}
+	if rating == nil {
+		rating = &common.Rating{}
+	}
+	moves := make([]*RatingMove, 0)
+	oldV, newV := reflect.ValueOf(old).Elem(), reflect.ValueOf(rating).Elem()
+	for i := 0; i < newV.NumField(); i++ {
+		from, to := oldV.Field(i).String(), newV.Field(i).String()
+		if from != to {
+			location := strings.Split(newV.Type().Field(i).Tag.Get("json"), ",")[0]
+			moves = append(moves, &RatingMove{Location: location, From: from, To: to})
+		}
+	}
+	return moves
+}
+
+func jsonKey(v interface{}) string {
+	data, _ := json.Marshal(v)
+	return string(data)
+}
+
+// History compares every snapshot to the previous one, newest first, limit is ignored when 0
+func History(dataDirectory string, limit int) ([]*Changelog, error) {
+	versions, errVersions := snapshot.Versions(dataDirectory)
+	if errVersions != nil {
+		return nil, errVersions
+	}
+	changelogs := make([]*Changelog, 0)
+	for idx := len(versions) - 1; idx > 0; idx-- {
+		if limit > 0 && len(changelogs) == limit {
+			break
+		}
+		changelog, errLoad := Load(dataDirectory, versions[idx-1], versions[idx])
+		if errLoad != nil {
+			return nil, errLoad
+		}
+		changelogs = append(changelogs, changelog)
+	}
+	return changelogs, nil
+}
@@ -0,0 +1,118 @@
+package changelog
+
+import (
+	"bytes"
+	"embed"
+	"encoding/xml"
+	"fmt"
+	htmltemplate "html/template"
+	"io"
+	"strings"
+	texttemplate "text/template"
+	"time"
+)
+
+//go:embed templates/*
+var templates embed.FS
+
+const (
+	Format_Markdown = "md"
+	Format_HTML     = "html"
+)
+
+func funcMap(siteURL string) map[string]interface{} {
+	return map[string]interface{}{
+		"link": func(websiteLink string) string {
+			return fmt.Sprintf("%s%s", strings.TrimSuffix(siteURL, "/"), websiteLink)
+		},
+		"join": strings.Join,
+		"location": func(location string) string {
+			return strings.Replace(location, "_", " ", -1)
+		},
+		"grade": func(grade string) string {
+			if grade == "" {
+				return "unrated"
+			}
+			return grade
+		},
+	}
+}
+
+// Render writes the changelog in Markdown or HTML, links to champions are made absolute with siteURL
+func (c *Changelog) Render(w io.Writer, format, siteURL string) error {
+	switch format {
+	case Format_Markdown:
+		tmpl, errTmpl := texttemplate.New("changelog.md").Funcs(funcMap(siteURL)).ParseFS(templates, "templates/changelog.md")
+		if errTmpl != nil {
+			return errTmpl
+		}
+		return tmpl.Execute(w, c)
+	case Format_HTML:
+		tmpl, errTmpl := htmltemplate.New("changelog.html").Funcs(funcMap(siteURL)).ParseFS(templates, "templates/changelog.html")
+		if errTmpl != nil {
+			return errTmpl
+		}
+		return tmpl.Execute(w, c)
+	}
+	return fmt.Errorf("unknown format %s", format)
+}
+
+type atomFeed struct {
+	XMLName xml.Name     `xml:"http://www.w3.org/2005/Atom feed"`
+	Title   string       `xml:"title"`
+	ID      string       `xml:"id"`
+	Updated string       `xml:"updated"`
+	Links   []atomLink   `xml:"link"`
+	Entries []*atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+}
+
+type atomEntry struct {
+	Title   string      `xml:"title"`
+	ID      string      `xml:"id"`
+	Updated string      `xml:"updated"`
+	Link    atomLink    `xml:"link"`
+	Content atomContent `xml:"content"`
+}
+
+type atomContent struct {
+	Type string `xml:"type,attr"`
+	Body string `xml:",chardata"`
+}
+
+// Feed renders changelogs as an Atom feed, feedURL is where the feed is published and
+// changelogURL the page listing the changelogs
+func Feed(changelogs []*Changelog, siteURL, feedURL, changelogURL string) ([]byte, error) {
+	feed := &atomFeed{
+		Title:   "RAID - Codex changelog",
+		ID:      feedURL,
+		Updated: time.Now().UTC().Format(time.RFC3339),
+		Links:   []atomLink{{Href: feedURL, Rel: "self"}, {Href: changelogURL}},
+		Entries: make([]*atomEntry, 0, len(changelogs)),
+	}
+	if len(changelogs) > 0 {
+		feed.Updated = changelogs[0].Date.UTC().Format(time.RFC3339)
+	}
+	for _, c := range changelogs {
+		content := bytes.NewBufferString("")
+		if errRender := c.Render(content, Format_HTML, siteURL); errRender != nil {
+			return nil, errRender
+		}
+		feed.Entries = append(feed.Entries, &atomEntry{
+			Title:   fmt.Sprintf("Changes from %s to %s", c.From, c.To),
+			ID:      fmt.Sprintf("%s#%s", changelogURL, c.To),
+			Updated: c.Date.UTC().Format(time.RFC3339),
+			Link:    atomLink{Href: fmt.Sprintf("%s#%s", changelogURL, c.To)},
+			Content: atomContent{Type: "html", Body: content.String()},
+		})
+	}
+	data, errXML := xml.MarshalIndent(feed, "", "  ")
+	if errXML != nil {
+		return nil, errXML
+	}
+	return append([]byte(xml.Header), data...), nil
+}
@@ -0,0 +1,55 @@
+package champions_giid_set
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/juju/errors"
+	"github.com/raid-codex/tools/common"
+	"github.com/raid-codex/tools/utils"
+	"gopkg.in/alecthomas/kingpin.v2"
+)
+
+type Command struct {
+	DataDirectory *string
+	Name          *string
+	GIID          *string
+}
+
+func New(cmd *kingpin.CmdClause) *Command {
+	return &Command{
+		DataDirectory: cmd.Flag("data-directory", "Data directory").Required().String(),
+		Name:          cmd.Flag("name", "Name of the champion").Required().String(),
+		GIID:          cmd.Flag("giid", "Identifier of the champion in the game").Required().String(),
+	}
+}
+
+func (c *Command) Run() {
+	errFactory := common.InitFactory(*c.DataDirectory)
+	if errFactory != nil {
+		utils.Exit(1, errFactory)
+	}
+	found, errFind := common.FindChampion(*c.Name)
+	if errFind != nil {
+		utils.Exit(1, errFind)
+	}
+	filename := fmt.Sprintf("%s/docs/champions/current/%s.json", *c.DataDirectory, found.Slug)
+	file, errFile := os.Open(filename)
+	if errFile != nil {
+		utils.Exit(1, errors.Annotate(errFile, "cannot open file"))
+	}
+	var champion common.Champion
+	errJSON := json.NewDecoder(file).Decode(&champion)
+	file.Close()
+	if errJSON != nil {
+		utils.Exit(1, errors.Annotate(errJSON, "cannot unmarshal file"))
+	}
+	champion.GIID = *c.GIID
+	// the thumbnail is the hash of the giid, it is computed again on the next sanitize
+	champion.Thumbnail = ""
+	errWrite := utils.WriteToFile(filename, champion)
+	if errWrite != nil {
+		utils.Exit(1, errWrite)
+	}
+}
@@ -0,0 +1,72 @@
+package champions_import
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/raid-codex/tools/common"
+	"github.com/raid-codex/tools/importer"
+	"github.com/raid-codex/tools/utils"
+	"gopkg.in/alecthomas/kingpin.v2"
+)
+
+type Command struct {
+	DataDirectory *string
+	Mapping       *string
+	CSVFile       *string
+	AllowUnknown  *bool
+	MergePolicy   *string
+	ReviewQueue   *string
+}
+
+func New(cmd *kingpin.CmdClause) *Command {
+	return &Command{
+		DataDirectory: cmd.Flag("data-directory", "Data directory").Required().String(),
+		Mapping:       cmd.Flag("mapping", fmt.Sprintf("Mapping file, or name of a built-in mapping (%s)", strings.Join(importer.BuiltinMappings(), ", "))).Required().String(),
+		CSVFile:       cmd.Flag("csv-file", "CSV File to import").Required().String(),
+		AllowUnknown:  cmd.Flag("allow-unknown", "Save known champions even if some lines match no champion").Bool(),
+		MergePolicy:   cmd.Flag("merge-policy", "JSON file with per-field source priorities, built-in priorities are used when not set").String(),
+		ReviewQueue:   cmd.Flag("review-queue", "JSON file in which conflicting values are queued for review").Default("review-queue.json").String(),
+	}
+}
+
+func (c *Command) Run() {
+	errFactory := common.InitFactory(*c.DataDirectory)
+	if errFactory != nil {
+		utils.Exit(1, errFactory)
+	}
+	mapping, errMapping := importer.LoadMapping(*c.Mapping)
+	if errMapping != nil {
+		utils.Exit(1, errMapping)
+	}
+	championsDirectory := fmt.Sprintf("%s/docs/champions/current", *c.DataDirectory)
+	imp, errImporter := importer.New(mapping, championsDirectory, championsDirectory)
+	if errImporter != nil {
+		utils.Exit(1, errImporter)
+	}
+	merger, errMerger := common.NewMerger(*c.MergePolicy, *c.ReviewQueue)
+	if errMerger != nil {
+		utils.Exit(1, errMerger)
+	}
+	imp.Merger = merger
+	imp.Sanitize = true
+	result, errImport := imp.ImportFile(*c.CSVFile)
+	if errImport != nil {
+		utils.Exit(1, errImport)
+	}
+	if errUnknown := result.ErrUnknown(); errUnknown != nil && !*c.AllowUnknown {
+		utils.Exit(1, errUnknown)
+	} else if errUnknown != nil {
+		log.Printf("%s\n", errUnknown)
+	}
+	errSave := imp.Save(result)
+	if errSave != nil {
+		utils.Exit(1, errSave)
+	}
+	errQueue := merger.Queue.Save()
+	if errQueue != nil {
+		utils.Exit(1, errQueue)
+	}
+	log.Printf("%d champions updated from %s\n", len(result.Updated), *c.CSVFile)
+}
@@ -0,0 +1,43 @@
+package champions_list
+
+import (
+	"fmt"
+
+	"github.com/raid-codex/tools/common"
+	"github.com/raid-codex/tools/utils"
+	"gopkg.in/alecthomas/kingpin.v2"
+)
+
+type Command struct {
+	DataDirectory *string
+	Where         *string
+}
+
+func New(cmd *kingpin.CmdClause) *Command {
+	return &Command{
+		DataDirectory: cmd.Flag("data-directory", "Data directory").Required().String(),
+		Where:         cmd.Flag("where", `Filter expression, e.g. 'rarity = epic and element = void and effect.A1 = poison and rating.clan_boss_with_giant_slayer >= A'`).String(),
+	}
+}
+
+func (c *Command) Run() {
+	filters := make([]common.ChampionFilter, 0)
+	if *c.Where != "" {
+		filter, errFilter := common.ParseChampionFilter(*c.Where)
+		if errFilter != nil {
+			utils.Exit(1, errFilter)
+		}
+		filters = append(filters, filter)
+	}
+	errFactory := common.InitFactory(*c.DataDirectory)
+	if errFactory != nil {
+		utils.Exit(1, errFactory)
+	}
+	champions, errChampions := common.GetChampions(filters...)
+	if errChampions != nil {
+		utils.Exit(1, errChampions)
+	}
+	for _, champion := range champions {
+		fmt.Printf("%s\t%s\n", champion.Slug, champion.Name)
+	}
+}
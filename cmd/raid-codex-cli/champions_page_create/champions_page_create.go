@@ -3,12 +3,16 @@ package champions_page_create
 import (
 	"encoding/json"
 	"fmt"
+	"html/template"
+	"io/ioutil"
 	"os"
 
 	"github.com/juju/errors"
 	"github.com/raid-codex/tools/common"
+	"github.com/raid-codex/tools/media"
+	"github.com/raid-codex/tools/publish"
+	"github.com/raid-codex/tools/templatefuncs"
 	"github.com/raid-codex/tools/utils"
-	"github.com/raid-codex/tools/utils/wp"
 	"gopkg.in/alecthomas/kingpin.v2"
 )
 
@@ -16,38 +20,75 @@ type Command struct {
 	ChampionFile   *string
 	TemplateFolder *string
 	DataDirectory  *string
+	Status         *string
+	PublishAt      *string
+	ImageManifest  *string
+	ProbeImages    *bool
 }
 
 func New(cmd *kingpin.CmdClause) *Command {
 	return &Command{
 		ChampionFile:   cmd.Flag("champion-file", "Filename for the champion").Required().String(),
-		TemplateFolder: cmd.Flag("template-folder", "Template folder").String(),
-		DataDirectory:  cmd.Flag("data-directory", "Data directory").String(),
+		TemplateFolder: cmd.Flag("template-folder", "Template folder").Required().String(),
+		DataDirectory:  cmd.Flag("data-directory", "Data directory").Required().String(),
+		Status:         cmd.Flag("status", "Status of the page: private, draft, publish or future, new pages are private and existing ones keep their status when not set").String(),
+		PublishAt:      cmd.Flag("publish-at", "RFC 3339 date the page goes live at, schedules the page").String(),
+		ImageManifest:  cmd.Flag("image-manifest", "Image manifest written by website media manifest, the images missing from it are replaced by their fallbacks, IMAGE_MANIFEST when not set").String(),
+		ProbeImages:    cmd.Flag("probe-images", "Without a manifest, probe the images over HTTP to replace the missing ones by their fallbacks, the results are cached in IMAGE_CACHE").Bool(),
 	}
 }
 
 func (c *Command) Run() {
-	client := wp.GetWPClient()
+	schedule, errSchedule := publish.ParseSchedule(*c.Status, *c.PublishAt)
+	if errSchedule != nil {
+		utils.Exit(1, errSchedule)
+	}
+	publisher, errPublisher := publish.FromEnv()
+	if errPublisher != nil {
+		utils.Exit(1, errPublisher)
+	}
+	errFactory := common.InitFactory(*c.DataDirectory)
+	if errFactory != nil {
+		utils.Exit(1, errFactory)
+	}
+	images, errImages := media.ResolverFromEnv(*c.ImageManifest, *c.ProbeImages)
+	if errImages != nil {
+		utils.Exit(1, errImages)
+	}
+	templatefuncs.UseImages(images)
+	tmpl, errTmpl := c.loadTemplates()
+	if errTmpl != nil {
+		utils.Exit(1, errTmpl)
+	}
 	champion, errChampion := c.getChampion()
 	if errChampion != nil {
 		utils.Exit(1, errChampion)
 	}
-	content := fmt.Sprintf(`[raid-codex-champion-page slug="%s"]`, champion.GetPageSlug())
-	page, errPage := wp.GetPageFromSlug(client, champion.GetPageSlug())
-	if errPage != nil && !errors.IsNotFound(errPage) {
-		utils.Exit(1, errPage)
-	} else if errPage != nil && errors.IsNotFound(errPage) {
-		errCreate := wp.CreatePage_Content(client, champion, content)
-		if errCreate != nil {
-			utils.Exit(1, errCreate)
-		}
-	} else {
-		errUpdate := wp.UpdatePage_Content(client, page, champion, content)
-		if errUpdate != nil {
-			utils.Exit(1, errUpdate)
-		}
+	content, errContent := publish.Content(champion, "this-is-for-compat", *c.DataDirectory, tmpl)
+	if errContent != nil {
+		utils.Exit(1, errors.Annotatef(errContent, "error while creating page"))
+	}
+	hashes, errHashes := publish.HashStoreFromEnv()
+	if errHashes != nil {
+		utils.Exit(1, errHashes)
+	}
+	result, errPublish := publish.Publish(publisher, hashes, champion, content, schedule)
+	if errPublish != nil {
+		utils.Exit(1, errPublish)
 	}
+	errSave := hashes.Save()
+	if errSave != nil {
+		utils.Exit(1, errSave)
+	}
+	errSaveImages := images.Save()
+	if errSaveImages != nil {
+		utils.Exit(1, errSaveImages)
+	}
+	summary := &publish.Summary{}
+	summary.Add(champion.GetPageSlug(), result)
+	fmt.Println(summary)
 }
+
 func (c *Command) getChampion() (*common.Champion, error) {
 	file, errFile := os.Open(*c.ChampionFile)
 	if errFile != nil {
@@ -62,3 +103,15 @@ func (c *Command) getChampion() (*common.Champion, error) {
 	}
 	return &champion, nil
 }
+
+func (c *Command) loadTemplates() (*template.Template, error) {
+	files, errFiles := ioutil.ReadDir(*c.TemplateFolder)
+	if errFiles != nil {
+		return nil, errFiles
+	}
+	templateFiles := make([]string, 0)
+	for _, file := range files {
+		templateFiles = append(templateFiles, fmt.Sprintf("%s/%s", *c.TemplateFolder, file.Name()))
+	}
+	return template.New("main.html").Funcs(templatefuncs.FuncMap).ParseFiles(templateFiles...)
+}
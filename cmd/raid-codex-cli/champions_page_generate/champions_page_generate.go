@@ -12,6 +12,7 @@ import (
 
 	"github.com/juju/errors"
 	"github.com/raid-codex/tools/common"
+	"github.com/raid-codex/tools/media"
 	"github.com/raid-codex/tools/templatefuncs"
 	"github.com/raid-codex/tools/utils"
 	"gopkg.in/alecthomas/kingpin.v2"
@@ -24,6 +25,8 @@ type Command struct {
 	PageTemplate   *string
 	DataDirectory  *string
 	NoPage         *bool
+	ImageManifest  *string
+	ProbeImages    *bool
 }
 
 func New(cmd *kingpin.CmdClause) *Command {
@@ -34,6 +37,8 @@ func New(cmd *kingpin.CmdClause) *Command {
 		OutputFile:     cmd.Flag("output-file", "Output file").Required().String(),
 		PageTemplate:   cmd.Flag("page-template", "Page template file").String(),
 		NoPage:         cmd.Flag("no-page", "Set this flag to skip page-template parameter. Generates HTML without any header/footer").Bool(),
+		ImageManifest:  cmd.Flag("image-manifest", "Image manifest written by website media manifest, the images missing from it are replaced by their fallbacks, IMAGE_MANIFEST when not set").String(),
+		ProbeImages:    cmd.Flag("probe-images", "Without a manifest, probe the images over HTTP to replace the missing ones by their fallbacks, the results are cached in IMAGE_CACHE").Bool(),
 	}
 }
 
@@ -42,6 +47,11 @@ func (c *Command) Run() {
 	if errInit != nil {
 		utils.Exit(1, errInit)
 	}
+	images, errImages := media.ResolverFromEnv(*c.ImageManifest, *c.ProbeImages)
+	if errImages != nil {
+		utils.Exit(1, errImages)
+	}
+	templatefuncs.UseImages(images)
 	champion, errChampion := c.getChampion()
 	if errChampion != nil {
 		utils.Exit(1, errChampion)
@@ -76,7 +86,7 @@ func (c *Command) Run() {
 			utils.Exit(1, errOutput)
 		}
 		defer outputFile.Close()
-		errExecute := tmpl.Execute(outputFile, map[string]interface{}{"Page": buf.String()})
+		errExecute := tmpl.Execute(outputFile, map[string]interface{}{"Page": buf.String(), "Title": champion.GetPageTitle()})
 		if errExecute != nil {
 			utils.Exit(1, errExecute)
 		}
@@ -90,6 +100,10 @@ func (c *Command) Run() {
 			utils.Exit(1, errWrite)
 		}
 	}
+	errSaveImages := images.Save()
+	if errSaveImages != nil {
+		utils.Exit(1, errSaveImages)
+	}
 }
 
 func (c *Command) loadTemplates() (*template.Template, error) {
@@ -6,7 +6,7 @@ import (
 
 	"github.com/juju/errors"
 	"github.com/raid-codex/tools/common"
-	"github.com/raid-codex/tools/seo/seowp"
+	"github.com/raid-codex/tools/publish"
 	"github.com/raid-codex/tools/utils"
 	"gopkg.in/alecthomas/kingpin.v2"
 )
@@ -58,7 +58,10 @@ func (c *Command) getChampion() (*common.Champion, error) {
 }
 
 func (c *Command) setDefault() {
-	c.champion.DefaultSEO()
+	errSEO := c.champion.DefaultSEO()
+	if errSEO != nil {
+		utils.Exit(1, errors.Annotate(errSEO, "cannot set default seo on champion"))
+	}
 	errWrite := utils.WriteToFile(*c.ChampionFile, c.champion)
 	if errWrite != nil {
 		utils.Exit(1, errors.Annotate(errWrite, "cannot set default seo on champion"))
@@ -66,7 +69,11 @@ func (c *Command) setDefault() {
 }
 
 func (c *Command) apply() {
-	errApply := seowp.Apply(c.champion.Slug, c.champion.SEO)
+	publisher, errPublisher := publish.FromEnv()
+	if errPublisher != nil {
+		utils.Exit(1, errPublisher)
+	}
+	errApply := publisher.ApplySEO(c.champion.Slug, c.champion.SEO)
 	if errApply != nil {
 		utils.Exit(1, errApply)
 	}
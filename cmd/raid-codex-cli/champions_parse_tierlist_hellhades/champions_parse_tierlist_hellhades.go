@@ -5,7 +5,6 @@ import (
 	"log"
 	"net/http"
 	"strconv"
-	"strings"
 
 	"github.com/PuerkitoBio/goquery"
 	"github.com/raid-codex/tools/common"
@@ -22,14 +21,6 @@ type Command struct {
 
 var (
 	ErrNotFound = fmt.Errorf("not found")
-	nameReplace = map[string]string{
-		"Centurian":             "Centurion",
-		"Steadfast Marshall":    "Steadfast Marshal",
-		"Woad Painted":          "Woad-Painted",
-		"Ma’Shalled":            "Ma'Shalled",
-		"Big ‘Un":               "Big'Un",
-		"Nogdar The Headhunter": "Nodgar the Headhunter",
-	}
 )
 
 func New(cmd *kingpin.CmdClause) *Command {
@@ -39,6 +30,10 @@ func New(cmd *kingpin.CmdClause) *Command {
 	return command
 }
 
+const tierListUrl = "https://www.hellhades.com/raid-shadow-legends-tier-list/"
+
+const rowSelector = ".post-content table.posts-data-table tr"
+
 const safeguard = `NameOverall RatingClan BossFaction WarsSpiderDragonFire KnightIce GolemArena DefArena Atk`
 
 func (c *Command) Run() {
@@ -46,13 +41,13 @@ func (c *Command) Run() {
 	if errInit != nil {
 		utils.Exit(1, errInit)
 	}
-	doc, errDoc := c.requestUrl("https://www.hellhades.com/raid-shadow-legends-tier-list/")
+	doc, errDoc := c.requestUrl(tierListUrl)
 	if errDoc != nil {
 		utils.Exit(1, errDoc)
 	}
 	errors := make([]error, 0)
 	champions := make([]*common.Champion, 0)
-	doc.Find(".post-content table.posts-data-table tr").Each(func(idx int, s *goquery.Selection) {
+	doc.Find(rowSelector).Each(func(idx int, s *goquery.Selection) {
 		if idx == 0 {
 			if s.Text() != safeguard {
 				utils.Exit(1, fmt.Errorf("invalid safe guard: '%s' instead of '%s'", s.Text(), safeguard))
@@ -62,20 +57,18 @@ func (c *Command) Run() {
 		var champion *common.Champion
 		var rating common.Rating
 		var name string
+		var notFound error
 		s.Find("td").Each(func(cIdx int, cS *goquery.Selection) {
 			switch cIdx {
 			case col_Name:
 				name = cS.Text()
-				if nameReplace[name] != "" {
-					name = nameReplace[name]
-				}
-				champions, errChampion := common.GetChampions(func(c *common.Champion) bool {
-					return strings.ToLower(c.Name) == strings.ToLower(name)
-				})
-				if errChampion != nil {
+				found, errChampion := common.FindChampion(name)
+				if _, ok := errChampion.(*common.ChampionNotFoundError); ok {
+					notFound = errChampion
+				} else if errChampion != nil {
 					utils.Exit(1, errChampion)
-				} else if len(champions) == 1 {
-					champion = champions[0]
+				} else {
+					champion = found
 				}
 			case col_ClanBoss:
 				rating.ClanBossWoGS = sanitizeRating(cS.Text())
@@ -97,10 +90,14 @@ func (c *Command) Run() {
 			}
 		})
 		if champion == nil {
-			errors = append(errors, fmt.Errorf("no champion named %s", name))
+			if notFound == nil {
+				notFound = fmt.Errorf("no champion named %s", name)
+			}
+			errors = append(errors, notFound)
 			return
 		}
 		champion.AddRating("hellhades-tier-list", &rating, 5)
+		champion.SetProvenance(common.RatingSourceProvenanceField("hellhades-tier-list"), common.ProvenanceSource_Hellhades, tierListUrl)
 		if errSanitize := champion.Sanitize(); errSanitize != nil {
 			errors = append(errors, errSanitize)
 		}
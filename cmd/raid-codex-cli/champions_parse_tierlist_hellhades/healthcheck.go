@@ -0,0 +1,17 @@
+package champions_parse_tierlist_hellhades
+
+import (
+	"github.com/raid-codex/tools/utils/scrapcheck"
+)
+
+// HealthCheck lists what the parser of this package expects from the tier list
+func HealthCheck() *scrapcheck.Check {
+	return &scrapcheck.Check{
+		Scraper: "hellhades tier list",
+		URL:     tierListUrl,
+		Expectations: []scrapcheck.Expectation{
+			{Description: "header row", Selector: rowSelector, Equals: safeguard},
+			{Description: "champion rows", Selector: rowSelector + " td", MinCount: col_ArenaOff + 1},
+		},
+	}
+}
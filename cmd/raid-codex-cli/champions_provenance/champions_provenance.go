@@ -0,0 +1,97 @@
+package champions_provenance
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/juju/errors"
+	"github.com/raid-codex/tools/common"
+	"github.com/raid-codex/tools/utils"
+	"gopkg.in/alecthomas/kingpin.v2"
+)
+
+type Command struct {
+	DataDirectory *string
+	ChampionSlug  *string
+	Field         *string
+	action        string
+}
+
+func New(cmd *kingpin.CmdClause, action string) *Command {
+	command := &Command{
+		action:        action,
+		DataDirectory: cmd.Flag("data-directory", "Data directory").Required().String(),
+		ChampionSlug:  cmd.Flag("champion-slug", "Slug of the champion").Required().String(),
+		Field:         cmd.Flag("field", "Field path (e.g. skills, characteristics.60), used as a prefix by show").String(),
+	}
+	return command
+}
+
+func (c *Command) Run() {
+	champion, errChampion := c.getChampion()
+	if errChampion != nil {
+		utils.Exit(1, errChampion)
+	}
+	switch c.action {
+	case "show":
+		c.show(champion)
+	case "lock", "unlock":
+		if *c.Field == "" {
+			utils.Exit(1, fmt.Errorf("--field is required to %s", c.action))
+		}
+		if c.action == "lock" {
+			champion.LockProvenance(*c.Field)
+		} else if errUnlock := champion.UnlockProvenance(*c.Field); errUnlock != nil {
+			utils.Exit(1, errUnlock)
+		}
+		errFactory := common.InitFactory(*c.DataDirectory)
+		if errFactory != nil {
+			utils.Exit(1, errFactory)
+		}
+		errSanitize := champion.Sanitize()
+		if errSanitize != nil {
+			utils.Exit(1, errSanitize)
+		}
+		errWrite := utils.WriteToFile(c.championFile(), champion)
+		if errWrite != nil {
+			utils.Exit(1, errWrite)
+		}
+	default:
+		utils.Exit(1, errors.NotImplementedf("action %s", c.action))
+	}
+}
+
+func (c *Command) show(champion *common.Champion) {
+	records := champion.GetProvenanceWithPrefix(*c.Field)
+	if len(records) == 0 {
+		fmt.Printf("no provenance recorded for %s\n", champion.Name)
+		return
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "FIELD\tSOURCE\tDATE\tLOCKED\tURL\n")
+	for _, record := range records {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%t\t%s\n", record.Field, record.Source, record.Date, record.Locked, record.URL)
+	}
+	w.Flush()
+}
+
+func (c *Command) championFile() string {
+	return fmt.Sprintf("%s/docs/champions/current/%s.json", *c.DataDirectory, *c.ChampionSlug)
+}
+
+func (c *Command) getChampion() (*common.Champion, error) {
+	file, errFile := os.Open(c.championFile())
+	if errFile != nil {
+		return nil, errors.Annotate(errFile, "cannot open file")
+	}
+	defer file.Close()
+
+	var champion common.Champion
+	errJSON := json.NewDecoder(file).Decode(&champion)
+	if errJSON != nil {
+		return nil, errors.Annotate(errJSON, "cannot unmarshal file")
+	}
+	return &champion, nil
+}
@@ -84,6 +84,7 @@ func (c *Command) Run() {
 	}
 
 	champion.AddRating(*c.Source, rating, *c.Weight)
+	champion.SetProvenance(common.RatingSourceProvenanceField(*c.Source), common.ProvenanceSource_Manual, "")
 	if errSanitize := champion.Sanitize(); errSanitize != nil {
 		utils.Exit(1, errSanitize)
 	}
@@ -94,17 +95,11 @@ func (c *Command) Run() {
 }
 
 func (c *Command) getChampion() (*common.Champion, error) {
-	nameOk, errSanitize := common.GetSanitizedName(*c.ChampionName)
-	if errSanitize != nil {
-		return nil, errSanitize
+	found, errFind := common.FindChampion(*c.ChampionName)
+	if errFind != nil {
+		return nil, errFind
 	}
-	champions, err := common.GetChampions(common.FilterChampionName(nameOk))
-	if err != nil {
-		return nil, err
-	} else if len(champions) != 1 {
-		return nil, fmt.Errorf("found %d champions with name %s", len(champions), nameOk)
-	}
-	file, errFile := os.Open(fmt.Sprintf("%s/docs/champions/current/%s.json", *c.DataDirectory, champions[0].Slug))
+	file, errFile := os.Open(fmt.Sprintf("%s/docs/champions/current/%s.json", *c.DataDirectory, found.Slug))
 	if errFile != nil {
 		return nil, errFile
 	}
@@ -0,0 +1,83 @@
+package champions_screenshots_process
+
+import (
+	"fmt"
+	"io/ioutil"
+	"log"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/raid-codex/tools/common"
+	"github.com/raid-codex/tools/media"
+	"github.com/raid-codex/tools/screenshot"
+	"github.com/raid-codex/tools/utils"
+	"gopkg.in/alecthomas/kingpin.v2"
+)
+
+type Command struct {
+	DataDirectory        *string
+	ScreenshotsDirectory *string
+	OutputDirectory      *string
+	OCR                  *string
+	TesseractBinary      *string
+	ThumbnailSize        *int
+	AcceptFuzzy          *bool
+}
+
+func New(cmd *kingpin.CmdClause) *Command {
+	return &Command{
+		DataDirectory:        cmd.Flag("data-directory", "Data directory").Required().String(),
+		ScreenshotsDirectory: cmd.Flag("screenshots-directory", "Directory of the screenshots of the champion pages of the game").Required().String(),
+		OutputDirectory:      cmd.Flag("output-directory", "Directory receiving the images, laid out like wp-content/uploads").Required().String(),
+		OCR:                  cmd.Flag("ocr", "Read the names on the screenshots with tesseract, or take them from the file names with none").Default("none").Enum("none", "tesseract"),
+		TesseractBinary:      cmd.Flag("tesseract-binary", "Path of tesseract").Default("tesseract").String(),
+		ThumbnailSize:        cmd.Flag("thumbnail-size", "Size of the hashed thumbnails, 0 to skip them").Default(strconv.Itoa(media.ThumbnailSize)).Int(),
+		AcceptFuzzy:          cmd.Flag("accept-fuzzy", "Process the screenshots whose name is only close to the name of a champion, they fail otherwise").Bool(),
+	}
+}
+
+func (c *Command) Run() {
+	errFactory := common.InitFactory(*c.DataDirectory)
+	if errFactory != nil {
+		utils.Exit(1, errFactory)
+	}
+	processor := &screenshot.Processor{
+		OutputDirectory: *c.OutputDirectory,
+		ThumbnailSize:   *c.ThumbnailSize,
+		AcceptFuzzy:     *c.AcceptFuzzy,
+	}
+	if *c.OCR == "tesseract" {
+		processor.OCR = &screenshot.Tesseract{Binary: *c.TesseractBinary, Language: "eng"}
+	}
+	files, errFiles := ioutil.ReadDir(*c.ScreenshotsDirectory)
+	if errFiles != nil {
+		utils.Exit(1, errFiles)
+	}
+	failed := make([]string, 0)
+	for _, file := range files {
+		switch strings.ToLower(filepath.Ext(file.Name())) {
+		case ".png", ".jpg", ".jpeg":
+		default:
+			continue
+		}
+		filename := filepath.Join(*c.ScreenshotsDirectory, file.Name())
+		result, errProcess := processor.Process(filename)
+		if errProcess != nil {
+			log.Printf("%s: %v\n", filename, errProcess)
+			failed = append(failed, file.Name())
+			continue
+		}
+		if result.Fuzzy != "" {
+			fmt.Printf("%s: %s (fuzzy match of %q)\n", file.Name(), result.Champion.Name, result.Fuzzy)
+		} else {
+			fmt.Printf("%s: %s\n", file.Name(), result.Champion.Name)
+		}
+		for _, written := range result.Files {
+			fmt.Printf("\t%s\n", written)
+		}
+	}
+	if len(failed) > 0 {
+		utils.Exit(1, fmt.Errorf("%d screenshots failed: %s", len(failed), strings.Join(failed, ", ")))
+	}
+}
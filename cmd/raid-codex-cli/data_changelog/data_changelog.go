@@ -0,0 +1,60 @@
+package data_changelog
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+
+	"github.com/raid-codex/tools/changelog"
+	"github.com/raid-codex/tools/snapshot"
+	"github.com/raid-codex/tools/utils"
+	"gopkg.in/alecthomas/kingpin.v2"
+)
+
+type Command struct {
+	DataDirectory *string
+	From          *string
+	To            *string
+	Format        *string
+	Output        *string
+	SiteURL       *string
+}
+
+func New(cmd *kingpin.CmdClause) *Command {
+	return &Command{
+		DataDirectory: cmd.Flag("data-directory", "Data directory").Required().String(),
+		From:          cmd.Flag("from", "Version to compare from, the latest snapshot by default").String(),
+		To:            cmd.Flag("to", "Version to compare to").Default(snapshot.Current).String(),
+		Format:        cmd.Flag("format", "Output format").Default(changelog.Format_Markdown).Enum(changelog.Format_Markdown, changelog.Format_HTML),
+		Output:        cmd.Flag("output", "File to write, the changelog is printed when not set").String(),
+		SiteURL:       cmd.Flag("site-url", "Root of the website, used for links to champions").Default("https://raid-codex.com").String(),
+	}
+}
+
+func (c *Command) Run() {
+	from := *c.From
+	if from == "" {
+		latest, errLatest := snapshot.Latest(*c.DataDirectory)
+		if errLatest != nil {
+			utils.Exit(1, errLatest)
+		} else if latest == "" {
+			utils.Exit(1, fmt.Errorf("no snapshot found, create one with data snapshot"))
+		}
+		from = latest
+	}
+	cl, errLoad := changelog.Load(*c.DataDirectory, from, *c.To)
+	if errLoad != nil {
+		utils.Exit(1, errLoad)
+	}
+	output := bytes.NewBufferString("")
+	if errRender := cl.Render(output, *c.Format, *c.SiteURL); errRender != nil {
+		utils.Exit(1, errRender)
+	}
+	if *c.Output == "" {
+		os.Stdout.Write(output.Bytes())
+		return
+	}
+	if errWrite := utils.WriteToFile(*c.Output, output.Bytes()); errWrite != nil {
+		utils.Exit(1, errWrite)
+	}
+}
@@ -0,0 +1,36 @@
+package data_check
+
+import (
+	"fmt"
+
+	"github.com/raid-codex/tools/datacheck"
+	"github.com/raid-codex/tools/utils"
+	"gopkg.in/alecthomas/kingpin.v2"
+)
+
+type Command struct {
+	DataDirectory   *string
+	ImagesDirectory *string
+}
+
+func New(cmd *kingpin.CmdClause) *Command {
+	return &Command{
+		DataDirectory:   cmd.Flag("data-directory", "Data directory").Required().String(),
+		ImagesDirectory: cmd.Flag("images-directory", "Directory holding the images, image slugs are only checked against their expected format when not set").String(),
+	}
+}
+
+func (c *Command) Run() {
+	checker := &datacheck.Checker{DataDirectory: *c.DataDirectory, ImagesDirectory: *c.ImagesDirectory}
+	links, errCheck := checker.Check()
+	if errCheck != nil {
+		utils.Exit(1, errCheck)
+	}
+	for _, link := range links {
+		fmt.Println(link)
+	}
+	if len(links) > 0 {
+		utils.Exit(1, fmt.Errorf("found %d broken links", len(links)))
+	}
+	fmt.Println("No broken link found")
+}
@@ -0,0 +1,39 @@
+package data_feed
+
+import (
+	"fmt"
+
+	"github.com/raid-codex/tools/changelog"
+	"github.com/raid-codex/tools/utils"
+	"gopkg.in/alecthomas/kingpin.v2"
+)
+
+type Command struct {
+	DataDirectory *string
+	Output        *string
+	Limit         *int
+	SiteURL       *string
+}
+
+func New(cmd *kingpin.CmdClause) *Command {
+	return &Command{
+		DataDirectory: cmd.Flag("data-directory", "Data directory").Required().String(),
+		Output:        cmd.Flag("output", "Atom file to write").Default("changelog.xml").String(),
+		Limit:         cmd.Flag("limit", "Number of snapshots in the feed, 0 for all").Default("20").Int(),
+		SiteURL:       cmd.Flag("site-url", "Root of the website").Default("https://raid-codex.com").String(),
+	}
+}
+
+func (c *Command) Run() {
+	changelogs, errHistory := changelog.History(*c.DataDirectory, *c.Limit)
+	if errHistory != nil {
+		utils.Exit(1, errHistory)
+	}
+	feed, errFeed := changelog.Feed(changelogs, *c.SiteURL, fmt.Sprintf("%s/changelog.xml", *c.SiteURL), fmt.Sprintf("%s/changelog/", *c.SiteURL))
+	if errFeed != nil {
+		utils.Exit(1, errFeed)
+	}
+	if errWrite := utils.WriteToFile(*c.Output, feed); errWrite != nil {
+		utils.Exit(1, errWrite)
+	}
+}
@@ -0,0 +1,30 @@
+package data_snapshot
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/raid-codex/tools/snapshot"
+	"github.com/raid-codex/tools/utils"
+	"gopkg.in/alecthomas/kingpin.v2"
+)
+
+type Command struct {
+	DataDirectory *string
+	Version       *string
+}
+
+func New(cmd *kingpin.CmdClause) *Command {
+	return &Command{
+		DataDirectory: cmd.Flag("data-directory", "Data directory").Required().String(),
+		Version:       cmd.Flag("version", "Date of the snapshot").Default(time.Now().Format(snapshot.VersionFormat)).String(),
+	}
+}
+
+func (c *Command) Run() {
+	errSnapshot := snapshot.Create(*c.DataDirectory, *c.Version)
+	if errSnapshot != nil {
+		utils.Exit(1, errSnapshot)
+	}
+	fmt.Printf("Snapshot %s created\n", *c.Version)
+}
@@ -0,0 +1,83 @@
+package export_sheet
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/raid-codex/tools/common"
+	"github.com/raid-codex/tools/export"
+	"github.com/raid-codex/tools/utils"
+	"gopkg.in/alecthomas/kingpin.v2"
+)
+
+type Command struct {
+	DataDirectory *string
+	Output        *string
+	Columns       *[]string
+	format        string
+}
+
+func New(cmd *kingpin.CmdClause, format string) *Command {
+	output := "Directory receiving champions.csv, builds.csv, masteries.csv and the champions.mapping.json to import champions.csv back, builds.csv and masteries.csv are export-only"
+	if format == "xlsx" {
+		output = "Workbook to create, with a sheet for champions, builds and masteries, the builds and masteries sheets are export-only"
+	}
+	return &Command{
+		format:        format,
+		DataDirectory: cmd.Flag("data-directory", "Data directory").Required().String(),
+		Output:        cmd.Flag("output", output).Required().String(),
+		Columns: cmd.Flag("columns", fmt.Sprintf("Column groups of the champions sheet (%s), all by default", strings.Join(export.ColumnGroups, ", "))).
+			Default(export.ColumnGroups...).Enums(export.ColumnGroups...),
+	}
+}
+
+func (c *Command) Run() {
+	errFactory := common.InitFactory(*c.DataDirectory)
+	if errFactory != nil {
+		utils.Exit(1, errFactory)
+	}
+	champions, errChampions := common.GetChampions()
+	if errChampions != nil {
+		utils.Exit(1, errChampions)
+	}
+	champions = export.SortedChampions(champions)
+	championsTable, errTable := export.ChampionsTable(champions, *c.Columns)
+	if errTable != nil {
+		utils.Exit(1, errTable)
+	}
+	tables := []*export.Table{championsTable, export.BuildsTable(champions), export.MasteriesTable(champions)}
+	switch c.format {
+	case "csv":
+		if errCSV := c.writeCSV(tables); errCSV != nil {
+			utils.Exit(1, errCSV)
+		}
+	case "xlsx":
+		if errXLSX := export.WriteXLSX(*c.Output, tables...); errXLSX != nil {
+			utils.Exit(1, errXLSX)
+		}
+	}
+}
+
+func (c *Command) writeCSV(tables []*export.Table) error {
+	if errMkdir := os.MkdirAll(*c.Output, 0755); errMkdir != nil {
+		return errMkdir
+	}
+	for _, table := range tables {
+		file, errFile := os.Create(filepath.Join(*c.Output, fmt.Sprintf("%s.csv", table.Name)))
+		if errFile != nil {
+			return errFile
+		}
+		errWrite := table.WriteCSV(file)
+		file.Close()
+		if errWrite != nil {
+			return errWrite
+		}
+	}
+	mapping, errMapping := tables[0].Mapping("raid-codex-export")
+	if errMapping != nil {
+		return errMapping
+	}
+	return utils.WriteToFile(filepath.Join(*c.Output, "champions.mapping.json"), mapping)
+}
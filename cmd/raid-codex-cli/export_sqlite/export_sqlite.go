@@ -0,0 +1,31 @@
+package export_sqlite
+
+import (
+	"github.com/raid-codex/tools/common"
+	"github.com/raid-codex/tools/export"
+	"github.com/raid-codex/tools/utils"
+	"gopkg.in/alecthomas/kingpin.v2"
+)
+
+type Command struct {
+	DataDirectory *string
+	Output        *string
+}
+
+func New(cmd *kingpin.CmdClause) *Command {
+	return &Command{
+		DataDirectory: cmd.Flag("data-directory", "Data directory").Required().String(),
+		Output:        cmd.Flag("output", "SQLite database to create, it is replaced if it exists").Default("raid-codex.sqlite").String(),
+	}
+}
+
+func (c *Command) Run() {
+	errFactory := common.InitFactory(*c.DataDirectory)
+	if errFactory != nil {
+		utils.Exit(1, errFactory)
+	}
+	errExport := export.SQLite(*c.Output)
+	if errExport != nil {
+		utils.Exit(1, errExport)
+	}
+}
@@ -9,9 +9,10 @@ import (
 
 	"github.com/juju/errors"
 	"github.com/raid-codex/tools/common"
+	"github.com/raid-codex/tools/media"
+	"github.com/raid-codex/tools/publish"
 	"github.com/raid-codex/tools/templatefuncs"
 	"github.com/raid-codex/tools/utils"
-	"github.com/raid-codex/tools/utils/wp"
 	"gopkg.in/alecthomas/kingpin.v2"
 )
 
@@ -19,6 +20,10 @@ type Command struct {
 	FactionFile    *string
 	DataDirectory  *string
 	TemplateFolder *string
+	Status         *string
+	PublishAt      *string
+	ImageManifest  *string
+	ProbeImages    *bool
 }
 
 func New(cmd *kingpin.CmdClause) *Command {
@@ -26,16 +31,31 @@ func New(cmd *kingpin.CmdClause) *Command {
 		FactionFile:    cmd.Flag("faction-file", "Filename for the faction").Required().String(),
 		TemplateFolder: cmd.Flag("template-folder", "Template folder").Required().String(),
 		DataDirectory:  cmd.Flag("data-directory", "Data directory").Required().String(),
+		Status:         cmd.Flag("status", "Status of the page: private, draft, publish or future, new pages are private and existing ones keep their status when not set").String(),
+		PublishAt:      cmd.Flag("publish-at", "RFC 3339 date the page goes live at, schedules the page").String(),
+		ImageManifest:  cmd.Flag("image-manifest", "Image manifest written by website media manifest, the images missing from it are replaced by their fallbacks, IMAGE_MANIFEST when not set").String(),
+		ProbeImages:    cmd.Flag("probe-images", "Without a manifest, probe the images over HTTP to replace the missing ones by their fallbacks, the results are cached in IMAGE_CACHE").Bool(),
 	}
 }
 
 func (c *Command) Run() {
-	client := wp.GetWPClient()
-
+	schedule, errSchedule := publish.ParseSchedule(*c.Status, *c.PublishAt)
+	if errSchedule != nil {
+		utils.Exit(1, errSchedule)
+	}
+	publisher, errPublisher := publish.FromEnv()
+	if errPublisher != nil {
+		utils.Exit(1, errPublisher)
+	}
 	errFactory := common.InitFactory(*c.DataDirectory)
 	if errFactory != nil {
 		utils.Exit(1, errFactory)
 	}
+	images, errImages := media.ResolverFromEnv(*c.ImageManifest, *c.ProbeImages)
+	if errImages != nil {
+		utils.Exit(1, errImages)
+	}
+	templatefuncs.UseImages(images)
 	tmpl, errTmpl := c.loadTemplates()
 	if errTmpl != nil {
 		utils.Exit(1, errTmpl)
@@ -44,20 +64,29 @@ func (c *Command) Run() {
 	if errFaction != nil {
 		utils.Exit(1, errFaction)
 	}
-	page, errPage := wp.GetPageFromSlug(client, faction.GetPageSlug())
-	if errPage != nil && !errors.IsNotFound(errPage) {
-		utils.Exit(1, errPage)
-	} else if errPage != nil && errors.IsNotFound(errPage) {
-		errCreate := wp.CreatePage(client, faction, "this-is-for-compat", *c.DataDirectory, tmpl)
-		if errCreate != nil {
-			utils.Exit(1, errCreate)
-		}
-	} else {
-		errUpdate := wp.UpdatePage(client, page, faction, "this-is-for-compat", *c.DataDirectory, tmpl)
-		if errUpdate != nil {
-			utils.Exit(1, errUpdate)
-		}
+	content, errContent := publish.Content(faction, "this-is-for-compat", *c.DataDirectory, tmpl)
+	if errContent != nil {
+		utils.Exit(1, errors.Annotatef(errContent, "error while creating page"))
+	}
+	hashes, errHashes := publish.HashStoreFromEnv()
+	if errHashes != nil {
+		utils.Exit(1, errHashes)
+	}
+	result, errPublish := publish.Publish(publisher, hashes, faction, content, schedule)
+	if errPublish != nil {
+		utils.Exit(1, errPublish)
+	}
+	errSave := hashes.Save()
+	if errSave != nil {
+		utils.Exit(1, errSave)
+	}
+	errSaveImages := images.Save()
+	if errSaveImages != nil {
+		utils.Exit(1, errSaveImages)
 	}
+	summary := &publish.Summary{}
+	summary.Add(faction.GetPageSlug(), result)
+	fmt.Println(summary)
 }
 
 func (c *Command) getFaction() (*common.Faction, error) {
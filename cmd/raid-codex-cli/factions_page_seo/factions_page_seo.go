@@ -6,16 +6,17 @@ import (
 
 	"github.com/juju/errors"
 	"github.com/raid-codex/tools/common"
-	"github.com/raid-codex/tools/seo/seowp"
+	"github.com/raid-codex/tools/publish"
 	"github.com/raid-codex/tools/utils"
 	"gopkg.in/alecthomas/kingpin.v2"
 )
 
 type Command struct {
-	FactionFile *string
-	SetDefault  *bool
-	faction     *common.Faction
-	action      string
+	FactionFile   *string
+	DataDirectory *string
+	SetDefault    *bool
+	faction       *common.Faction
+	action        string
 }
 
 func New(cmd *kingpin.CmdClause, action string) *Command {
@@ -23,6 +24,9 @@ func New(cmd *kingpin.CmdClause, action string) *Command {
 		action:      action,
 		FactionFile: cmd.Flag("faction-file", "JSON file for the Faction. Data will be edited in place if needed").Required().String(),
 	}
+	if action == "set-default" {
+		command.DataDirectory = cmd.Flag("data-directory", "Directory containing all the game data").Required().String()
+	}
 	return command
 }
 
@@ -58,7 +62,14 @@ func (c *Command) getFaction() (*common.Faction, error) {
 }
 
 func (c *Command) setDefault() {
-	c.faction.DefaultSEO()
+	errFactory := common.InitFactory(*c.DataDirectory)
+	if errFactory != nil {
+		utils.Exit(1, errFactory)
+	}
+	errSEO := c.faction.DefaultSEO()
+	if errSEO != nil {
+		utils.Exit(1, errors.Annotate(errSEO, "cannot set default seo on faction"))
+	}
 	errWrite := utils.WriteToFile(*c.FactionFile, c.faction)
 	if errWrite != nil {
 		utils.Exit(1, errors.Annotate(errWrite, "cannot set default seo on faction"))
@@ -66,7 +77,11 @@ func (c *Command) setDefault() {
 }
 
 func (c *Command) apply() {
-	errApply := seowp.Apply(c.faction.Slug, c.faction.SEO)
+	publisher, errPublisher := publish.FromEnv()
+	if errPublisher != nil {
+		utils.Exit(1, errPublisher)
+	}
+	errApply := publisher.ApplySEO(c.faction.Slug, c.faction.SEO)
 	if errApply != nil {
 		utils.Exit(1, errApply)
 	}
@@ -14,17 +14,23 @@ import (
 type Command struct {
 	ChampionsDirectory *string
 	TargetFolder       *string
+	DataDirectory      *string
 }
 
 func New(cmd *kingpin.CmdClause) *Command {
 	command := &Command{
 		ChampionsDirectory: cmd.Flag("champions-directory", "Folder in which current champions are stored").Required().String(),
 		TargetFolder:       cmd.Flag("target-folder", "Folder in which to create the JSON files").Required().String(),
+		DataDirectory:      cmd.Flag("data-directory", "Directory containing all the game data").Required().String(),
 	}
 	return command
 }
 
 func (c *Command) Run() {
+	errFactory := common.InitFactory(*c.DataDirectory)
+	if errFactory != nil {
+		utils.Exit(1, errFactory)
+	}
 	champions, errChampions := c.fetchChampions()
 	if errChampions != nil {
 		utils.Exit(1, errChampions)
@@ -10,6 +10,7 @@ import (
 
 	"github.com/juju/errors"
 	"github.com/raid-codex/tools/common"
+	"github.com/raid-codex/tools/media"
 	"github.com/raid-codex/tools/templatefuncs"
 	"github.com/raid-codex/tools/utils"
 	"gopkg.in/alecthomas/kingpin.v2"
@@ -21,6 +22,8 @@ type Command struct {
 	OutputFile     *string
 	PageTemplate   *string
 	DataDirectory  *string
+	ImageManifest  *string
+	ProbeImages    *bool
 }
 
 func New(cmd *kingpin.CmdClause) *Command {
@@ -30,6 +33,8 @@ func New(cmd *kingpin.CmdClause) *Command {
 		TemplateFolder: cmd.Flag("template-folder", "Template folder").Required().String(),
 		OutputFile:     cmd.Flag("output-file", "Output file").Required().String(),
 		PageTemplate:   cmd.Flag("page-template", "Page template file").Required().String(),
+		ImageManifest:  cmd.Flag("image-manifest", "Image manifest written by website media manifest, the images missing from it are replaced by their fallbacks, IMAGE_MANIFEST when not set").String(),
+		ProbeImages:    cmd.Flag("probe-images", "Without a manifest, probe the images over HTTP to replace the missing ones by their fallbacks, the results are cached in IMAGE_CACHE").Bool(),
 	}
 }
 
@@ -38,6 +43,11 @@ func (c *Command) Run() {
 	if errInit != nil {
 		utils.Exit(1, errInit)
 	}
+	images, errImages := media.ResolverFromEnv(*c.ImageManifest, *c.ProbeImages)
+	if errImages != nil {
+		utils.Exit(1, errImages)
+	}
+	templatefuncs.UseImages(images)
 	fusion, errFusion := c.getFusion()
 	if errFusion != nil {
 		utils.Exit(1, errFusion)
@@ -68,10 +78,14 @@ func (c *Command) Run() {
 	if errTmpl != nil {
 		utils.Exit(1, errTmpl)
 	}
-	errExecute := tmpl.Execute(outputFile, map[string]interface{}{"Page": buf.String()})
+	errExecute := tmpl.Execute(outputFile, map[string]interface{}{"Page": buf.String(), "Title": fusion.GetPageTitle()})
 	if errExecute != nil {
 		utils.Exit(1, errExecute)
 	}
+	errSaveImages := images.Save()
+	if errSaveImages != nil {
+		utils.Exit(1, errSaveImages)
+	}
 }
 
 func (c *Command) loadTemplates() (*template.Template, error) {
@@ -7,16 +7,27 @@ import (
 	"strings"
 
 	"github.com/raid-codex/tools/cmd/raid-codex-cli/champions_characteristics_parser"
+	"github.com/raid-codex/tools/cmd/raid-codex-cli/champions_giid_set"
+	"github.com/raid-codex/tools/cmd/raid-codex-cli/champions_import"
+	"github.com/raid-codex/tools/cmd/raid-codex-cli/champions_list"
 	"github.com/raid-codex/tools/cmd/raid-codex-cli/champions_page_create"
 	"github.com/raid-codex/tools/cmd/raid-codex-cli/champions_page_generate"
 	"github.com/raid-codex/tools/cmd/raid-codex-cli/champions_page_seo"
 	"github.com/raid-codex/tools/cmd/raid-codex-cli/champions_parse_tierlist"
 	"github.com/raid-codex/tools/cmd/raid-codex-cli/champions_parse_tierlist_hellhades"
 	"github.com/raid-codex/tools/cmd/raid-codex-cli/champions_parser"
+	"github.com/raid-codex/tools/cmd/raid-codex-cli/champions_provenance"
 	"github.com/raid-codex/tools/cmd/raid-codex-cli/champions_rate"
 	"github.com/raid-codex/tools/cmd/raid-codex-cli/champions_rebuild_index"
 	"github.com/raid-codex/tools/cmd/raid-codex-cli/champions_sanitize"
+	"github.com/raid-codex/tools/cmd/raid-codex-cli/champions_screenshots_process"
 	"github.com/raid-codex/tools/cmd/raid-codex-cli/champions_video_add"
+	"github.com/raid-codex/tools/cmd/raid-codex-cli/data_changelog"
+	"github.com/raid-codex/tools/cmd/raid-codex-cli/data_check"
+	"github.com/raid-codex/tools/cmd/raid-codex-cli/data_feed"
+	"github.com/raid-codex/tools/cmd/raid-codex-cli/data_snapshot"
+	"github.com/raid-codex/tools/cmd/raid-codex-cli/export_sheet"
+	"github.com/raid-codex/tools/cmd/raid-codex-cli/export_sqlite"
 	"github.com/raid-codex/tools/cmd/raid-codex-cli/factions_page_create"
 	"github.com/raid-codex/tools/cmd/raid-codex-cli/factions_page_generate"
 	"github.com/raid-codex/tools/cmd/raid-codex-cli/factions_page_seo"
@@ -29,17 +40,27 @@ import (
 	"github.com/raid-codex/tools/cmd/raid-codex-cli/fusions_sanitize"
 	"github.com/raid-codex/tools/cmd/raid-codex-cli/parse_full_sheet"
 	"github.com/raid-codex/tools/cmd/raid-codex-cli/parse_static_data"
+	"github.com/raid-codex/tools/cmd/raid-codex-cli/schema_generate"
 	"github.com/raid-codex/tools/cmd/raid-codex-cli/schema_validate"
 	"github.com/raid-codex/tools/cmd/raid-codex-cli/scrap_ayumilove_champions"
+	"github.com/raid-codex/tools/cmd/raid-codex-cli/scrap_check"
 	"github.com/raid-codex/tools/cmd/raid-codex-cli/scrap_gameronion_champions"
 	"github.com/raid-codex/tools/cmd/raid-codex-cli/scrap_raidshadowlegendspro_champions"
 	"github.com/raid-codex/tools/cmd/raid-codex-cli/scrap_wikia_characteristics"
+	"github.com/raid-codex/tools/cmd/raid-codex-cli/search_export"
 	"github.com/raid-codex/tools/cmd/raid-codex-cli/server_run"
+	"github.com/raid-codex/tools/cmd/raid-codex-cli/site_build"
+	"github.com/raid-codex/tools/cmd/raid-codex-cli/site_pages_private"
+	"github.com/raid-codex/tools/cmd/raid-codex-cli/site_publish"
+	"github.com/raid-codex/tools/cmd/raid-codex-cli/site_sitemap"
 	"github.com/raid-codex/tools/cmd/raid-codex-cli/status_effects_page_create"
 	"github.com/raid-codex/tools/cmd/raid-codex-cli/status_effects_page_generate"
 	"github.com/raid-codex/tools/cmd/raid-codex-cli/status_effects_rebuild_index"
 	"github.com/raid-codex/tools/cmd/raid-codex-cli/status_effects_sanitize"
 	"github.com/raid-codex/tools/cmd/raid-codex-cli/website_cache_clear"
+	"github.com/raid-codex/tools/cmd/raid-codex-cli/website_media_generate"
+	"github.com/raid-codex/tools/cmd/raid-codex-cli/website_media_manifest"
+	"github.com/raid-codex/tools/cmd/raid-codex-cli/website_media_sync"
 	"github.com/raid-codex/tools/utils"
 	_ "github.com/raid-codex/tools/utils/logger" // init logger
 	"gopkg.in/alecthomas/kingpin.v2"
@@ -71,6 +92,9 @@ var (
 
 	champions = app.Command("champions", "do stuff with champions")
 
+	championsList    = champions.Command("list", "List champions, optionally filtered with an expression")
+	championsListCmd = champions_list.New(championsList)
+
 	championsVideo       = champions.Command("video", "Video")
 	championsVideoAdd    = championsVideo.Command("add", "Add video to champion")
 	championsVideoAddCmd = champions_video_add.New(championsVideoAdd)
@@ -89,6 +113,14 @@ var (
 	championsCharacteristicsParser    = championsCharacteristics.Command("parse", "parse champions characteristics")
 	championsCharacteristicsParserCmd = champions_characteristics_parser.New(championsCharacteristicsParser)
 
+	championsScreenshots           = champions.Command("screenshots", "Deal with screenshots of the champion pages of the game")
+	championsScreenshotsProcess    = championsScreenshots.Command("process", "Crop the portrait, thumbnail and statistics of every screenshot of a directory")
+	championsScreenshotsProcessCmd = champions_screenshots_process.New(championsScreenshotsProcess)
+
+	championsGIID       = champions.Command("giid", "Deal with the identifier of champions in the game")
+	championsGIIDSet    = championsGIID.Command("set", "Set the identifier of a champion in the game, its thumbnail is computed again on the next sanitize")
+	championsGIIDSetCmd = champions_giid_set.New(championsGIIDSet)
+
 	championsRebuildIndex    = champions.Command("rebuild-index", "rebuild champions index")
 	championsRebuildIndexCmd = champions_rebuild_index.New(championsRebuildIndex)
 
@@ -112,6 +144,17 @@ var (
 	championsParseTierListHellhades    = championsParse.Command("tier-list-hellhades", "Hellhades tier list")
 	championsParseTierListHellhadesCmd = champions_parse_tierlist_hellhades.New(championsParseTierListHellhades)
 
+	championsImport    = champions.Command("import", "Import champions data from a CSV file described by a mapping")
+	championsImportCmd = champions_import.New(championsImport)
+
+	championsProvenance          = champions.Command("provenance", "Deal with the provenance of champion fields")
+	championsProvenanceShow      = championsProvenance.Command("show", "Show where each field of a champion comes from")
+	championsProvenanceShowCmd   = champions_provenance.New(championsProvenanceShow, "show")
+	championsProvenanceLock      = championsProvenance.Command("lock", "Lock a field so its curated value is never overwritten by a scraper")
+	championsProvenanceLockCmd   = champions_provenance.New(championsProvenanceLock, "lock")
+	championsProvenanceUnlock    = championsProvenance.Command("unlock", "Unlock a field previously locked")
+	championsProvenanceUnlockCmd = champions_provenance.New(championsProvenanceUnlock, "unlock")
+
 	factions = app.Command("factions", "do stuff with factions")
 
 	factionsSanitize    = factions.Command("sanitize", "sanitize faction file")
@@ -156,11 +199,24 @@ var (
 	scrapRaidShadowLegendsProChampions    = scrapRaidShadowLegendsPro.Command("champions", "Scrap champions")
 	scrapRaidShadowLegendsProChampionsCmd = scrap_raidshadowlegendspro_champions.New(scrapRaidShadowLegendsProChampions)
 
+	scrapCheck    = scrap.Command("check", "Check that scraped pages still have the structure parsers expect")
+	scrapCheckCmd = scrap_check.New(scrapCheck)
+
 	website              = app.Command("website", "Stuff for website")
 	websiteCache         = website.Command("cache", "Stuff with website cache")
-	websiteCacheClear    = websiteCache.Command("clear", "Clear cache of website")
+	websiteCacheClear    = websiteCache.Command("clear", "Clear cache of website, only the pages of the changed entities when some are given")
 	websiteCacheClearCmd = website_cache_clear.New(websiteCacheClear)
 
+	websiteMedia        = website.Command("media", "Stuff with website media")
+	websiteMediaSync    = websiteMedia.Command("sync", "Check every image of the data is in an image folder and upload the missing ones")
+	websiteMediaSyncCmd = website_media_sync.New(websiteMediaSync)
+
+	websiteMediaManifest    = websiteMedia.Command("manifest", "List the images of a local mirror of the uploads, for the templates to resolve images offline")
+	websiteMediaManifestCmd = website_media_manifest.New(websiteMediaManifest)
+
+	websiteMediaGenerate    = websiteMedia.Command("generate", "Generate the resized and WebP variants of the champion images from their portrait")
+	websiteMediaGenerateCmd = website_media_generate.New(websiteMediaGenerate)
+
 	statusEffect = app.Command("status-effect", "Stuff for status effect")
 
 	statusEffectSanitize    = statusEffect.Command("sanitize", "Sanitize a status effect file")
@@ -179,6 +235,8 @@ var (
 	schema            = app.Command("schema", "Stuff for schemas")
 	schemaValidate    = schema.Command("validate", "Validate a file against a schema")
 	schemaValidateCmd = schema_validate.New(schemaValidate)
+	schemaGenerate    = schema.Command("generate", "Generate the JSON schemas of data files from the Go types")
+	schemaGenerateCmd = schema_generate.New(schemaGenerate)
 
 	parse              = app.Command("parse", "Parse stuff")
 	parseFullSheet     = parse.Command("full-sheet", "Parse the full-sheet stuff")
@@ -206,6 +264,46 @@ var (
 	serverRun    = server.Command("run", "Run the server")
 	serverRunCmd = server_run.New(serverRun)
 
+	siteCmd      = app.Command("site", "Stuff for the static site")
+	siteBuild    = siteCmd.Command("build", "Render every page, list and asset of the codex as a static site")
+	siteBuildCmd = site_build.New(siteBuild)
+
+	siteSitemap    = siteCmd.Command("sitemap", "Generate sitemap.xml, the sitemaps of every type of page and robots.txt")
+	siteSitemapCmd = site_sitemap.New(siteSitemap)
+
+	sitePublish    = siteCmd.Command("publish", "Publish the page of every entity and report pages matching no entity")
+	sitePublishCmd = site_publish.New(sitePublish)
+
+	sitePages           = siteCmd.Command("pages", "Stuff for the published pages")
+	sitePagesPrivate    = sitePages.Command("private", "List the pages of the entities still private")
+	sitePagesPrivateCmd = site_pages_private.New(sitePagesPrivate)
+
+	searchCmd       = app.Command("search", "Stuff for the search index")
+	searchExport    = searchCmd.Command("export", "Export the search index as JSON for client-side search")
+	searchExportCmd = search_export.New(searchExport)
+
+	data = app.Command("data", "Stuff for the whole data directory")
+
+	dataCheck    = data.Command("check", "Check every reference between data files and report the broken ones")
+	dataCheckCmd = data_check.New(dataCheck)
+
+	dataSnapshot     = data.Command("snapshot", "Freeze the current data into a dated version")
+	dataSnapshotCmd  = data_snapshot.New(dataSnapshot)
+	dataChangelog    = data.Command("changelog", "Describe what changed on champions between two versions")
+	dataChangelogCmd = data_changelog.New(dataChangelog)
+	dataFeed         = data.Command("feed", "Write the Atom feed of the changes between snapshots")
+	dataFeedCmd      = data_feed.New(dataFeed)
+
+	export = app.Command("export", "Export the dataset to other formats")
+
+	exportSQLite    = export.Command("sqlite", "Export the dataset to a normalized SQLite database")
+	exportSQLiteCmd = export_sqlite.New(exportSQLite)
+
+	exportCSV     = export.Command("csv", "Export champions, builds and masteries to CSV files")
+	exportCSVCmd  = export_sheet.New(exportCSV, "csv")
+	exportXLSX    = export.Command("xlsx", "Export champions, builds and masteries to a XLSX workbook")
+	exportXLSXCmd = export_sheet.New(exportXLSX, "xlsx")
+
 	runByCmd = map[string]Runnable{
 		"champions rating add-from-source":     championsRatingAddFromSourceCmd,
 		"champions parser":                     championsParserCmd,
@@ -221,6 +319,8 @@ var (
 		"champions page seo set-default":       championsPageSeoSetDefaultCmd,
 		"champions page seo apply":             championsPageSeoApplyCmd,
 		"champions rebuild-index":              championsRebuildIndexCmd,
+		"champions screenshots process":        championsScreenshotsProcessCmd,
+		"champions giid set":                   championsGIIDSetCmd,
 		"factions page seo set-default":        factionsPageSeoSetDefaultCmd,
 		"factions page seo apply":              factionsPageSeoApplyCmd,
 		"factions page generate":               factionsPageGenerateCmd,
@@ -229,6 +329,9 @@ var (
 		"champions video add":                  championsVideoAddCmd,
 		"factions sanitize":                    factionsSanitizeCmd,
 		"website cache clear":                  websiteCacheClearCmd,
+		"website media generate":               websiteMediaGenerateCmd,
+		"website media manifest":               websiteMediaManifestCmd,
+		"website media sync":                   websiteMediaSyncCmd,
 		"champions page generate":              championsPageGenerateCmd,
 		"schema validate":                      schemaValidateCmd,
 		"status-effect sanitize":               statusEffectSanitizeCmd,
@@ -243,5 +346,24 @@ var (
 		"fusions page generate":                fusionsPageGenerateCmd,
 		"fusions page create":                  fusionsPageCreateCmd,
 		"server run":                           serverRunCmd,
+		"champions import":                     championsImportCmd,
+		"scrap check":                          scrapCheckCmd,
+		"champions provenance show":            championsProvenanceShowCmd,
+		"champions provenance lock":            championsProvenanceLockCmd,
+		"champions provenance unlock":          championsProvenanceUnlockCmd,
+		"data check":                           dataCheckCmd,
+		"search export":                        searchExportCmd,
+		"champions list":                       championsListCmd,
+		"site build":                           siteBuildCmd,
+		"site pages private":                   sitePagesPrivateCmd,
+		"site publish":                         sitePublishCmd,
+		"site sitemap":                         siteSitemapCmd,
+		"data snapshot":                        dataSnapshotCmd,
+		"data changelog":                       dataChangelogCmd,
+		"data feed":                            dataFeedCmd,
+		"schema generate":                      schemaGenerateCmd,
+		"export csv":                           exportCSVCmd,
+		"export xlsx":                          exportXLSXCmd,
+		"export sqlite":                        exportSQLiteCmd,
 	}
 )
@@ -0,0 +1,22 @@
+package parse_full_sheet
+
+import (
+	"fmt"
+
+	"github.com/raid-codex/tools/importer"
+	"github.com/raid-codex/tools/utils/scrapcheck"
+)
+
+// SheetHealthCheck checks that file, a sheet of type sheetType exported as CSV, can be parsed
+func SheetHealthCheck(sheetType, file string) (*scrapcheck.Check, error) {
+	mapping, errMapping := importer.LoadMapping(fmt.Sprintf("full-sheet-%s", sheetType))
+	if errMapping != nil {
+		return nil, errMapping
+	}
+	return &scrapcheck.Check{
+		Scraper:   fmt.Sprintf("full sheet %s", sheetType),
+		URL:       file,
+		Header:    mapping.CheckHeader,
+		Delimiter: []rune(mapping.Delimiter)[0],
+	}, nil
+}
@@ -0,0 +1,37 @@
+package schema_generate
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/raid-codex/tools/schema"
+	"github.com/raid-codex/tools/utils"
+	"gopkg.in/alecthomas/kingpin.v2"
+)
+
+type Command struct {
+	OutputDirectory *string
+}
+
+func New(cmd *kingpin.CmdClause) *Command {
+	return &Command{
+		OutputDirectory: cmd.Flag("output-directory", "Directory receiving <entity>.schema.json and <entity>-index.schema.json for every entity").Required().String(),
+	}
+}
+
+func (c *Command) Run() {
+	if errMkdir := os.MkdirAll(*c.OutputDirectory, 0755); errMkdir != nil {
+		utils.Exit(1, errMkdir)
+	}
+	for _, entity := range schema.Entities {
+		errWrite := utils.WriteToFile(filepath.Join(*c.OutputDirectory, fmt.Sprintf("%s.schema.json", entity.Name)), entity.Generate())
+		if errWrite != nil {
+			utils.Exit(1, errWrite)
+		}
+		errWrite = utils.WriteToFile(filepath.Join(*c.OutputDirectory, fmt.Sprintf("%s-index.schema.json", entity.Name)), entity.GenerateIndex())
+		if errWrite != nil {
+			utils.Exit(1, errWrite)
+		}
+	}
+}
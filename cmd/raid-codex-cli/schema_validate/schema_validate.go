@@ -5,26 +5,36 @@ import (
 	"io/ioutil"
 	"strings"
 
+	"github.com/raid-codex/tools/schema"
 	"github.com/raid-codex/tools/utils"
 	"github.com/xeipuuv/gojsonschema"
 	"gopkg.in/alecthomas/kingpin.v2"
 )
 
 type Command struct {
-	File        *string
-	SchemaFile  *string
-	Definitions *string
+	File          *string
+	SchemaFile    *string
+	Definitions   *string
+	DataDirectory *string
 }
 
 func New(cmd *kingpin.CmdClause) *Command {
 	return &Command{
-		File:        cmd.Flag("file", "Filename to check").Required().String(),
-		SchemaFile:  cmd.Flag("schema-file", "Filename for the schema").Required().String(),
-		Definitions: cmd.Flag("definitions", "Filename for schema definitions").Required().String(),
+		File:          cmd.Flag("file", "Filename to check").String(),
+		SchemaFile:    cmd.Flag("schema-file", "Filename for the schema").String(),
+		Definitions:   cmd.Flag("definitions", "Filename for schema definitions").String(),
+		DataDirectory: cmd.Flag("data-directory", "Validate every file of the data directory against the schemas generated from the Go types instead").String(),
 	}
 }
 
 func (c *Command) Run() {
+	if *c.DataDirectory != "" {
+		c.validateDataDirectory()
+		return
+	}
+	if *c.File == "" || *c.SchemaFile == "" || *c.Definitions == "" {
+		utils.Exit(1, fmt.Errorf("--file, --schema-file and --definitions are required without --data-directory"))
+	}
 	definitions, err := ioutil.ReadFile(*c.Definitions)
 	if err != nil {
 		utils.Exit(1, err)
@@ -52,3 +62,19 @@ func (c *Command) Run() {
 		utils.Exit(1, fmt.Errorf(errMessage))
 	}
 }
+
+func (c *Command) validateDataDirectory() {
+	fileErrors, err := schema.ValidateDataDirectory(*c.DataDirectory)
+	if err != nil {
+		utils.Exit(1, err)
+	}
+	if len(fileErrors) == 0 {
+		fmt.Printf("The data directory is valid\n")
+		return
+	}
+	errMessage := fmt.Sprintf("%d files are not valid. see errors :\n", len(fileErrors))
+	for _, fileError := range fileErrors {
+		errMessage = fmt.Sprintf("%s%s\n", errMessage, fileError)
+	}
+	utils.Exit(1, fmt.Errorf(errMessage))
+}
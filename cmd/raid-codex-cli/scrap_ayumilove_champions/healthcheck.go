@@ -0,0 +1,23 @@
+package scrap_ayumilove_champions
+
+import (
+	"github.com/raid-codex/tools/common"
+	"github.com/raid-codex/tools/utils/scrapcheck"
+)
+
+// HealthCheck lists what the parsers of this package expect from the page of champion
+func HealthCheck(champion *common.Champion) *scrapcheck.Check {
+	return &scrapcheck.Check{
+		Scraper: "ayumilove champions",
+		URL:     championUrl(champion),
+		Expectations: []scrapcheck.Expectation{
+			{Description: "stats in 2nd <p> of 2nd column of first table", Selector: selectorTables + ":first-of-type td:nth-child(2) p:nth-of-type(2)", Contains: "ATK"},
+			{Description: "ratings table", Selector: selectorTables, MinCount: 2},
+			{Description: "skills heading", Selector: selectorContent + " > *", HasSuffix: headingSkills},
+			{Description: "equipment heading", Selector: selectorContent + " > *", HasSuffix: headingEquipment},
+			{Description: "masteries heading", Selector: selectorContent + " > *", HasSuffix: headingMasteries},
+			{Description: "masteries list", Selector: selectorContent + " " + selectorMasteries},
+			{Description: "storyline heading", Selector: selectorContent + " > *", HasSuffix: headingStoryline},
+		},
+	}
+}
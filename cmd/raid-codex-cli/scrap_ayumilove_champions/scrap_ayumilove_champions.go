@@ -24,6 +24,10 @@ type Command struct {
 	Ratings       *bool
 	Skills        *bool
 	Lore          *bool
+	MergePolicy   *string
+	ReviewQueue   *string
+	docUrl        string
+	merger        *common.Merger
 }
 
 func New(cmd *kingpin.CmdClause) *Command {
@@ -36,17 +40,27 @@ func New(cmd *kingpin.CmdClause) *Command {
 		Ratings:       cmd.Flag("with-ratings", "Fetch and store champion's rating").Bool(),
 		Skills:        cmd.Flag("with-skills", "Also parse champion's skills").Bool(),
 		Lore:          cmd.Flag("with-lore", "Also parse champion's lore").Bool(),
+		MergePolicy:   cmd.Flag("merge-policy", "JSON file with per-field source priorities, built-in priorities are used when not set").String(),
+		ReviewQueue:   cmd.Flag("review-queue", "JSON file in which conflicting values are queued for review").Default("review-queue.json").String(),
 	}
 }
 
 var (
-	slugTranslation = map[string]string{
-		"ma-shalled": "mashalled",
-		"khoronar":   "kohronar",
-	}
 	ErrNotFound = fmt.Errorf("not found")
 )
 
+// Layout of the pages the parsers rely on, HealthCheck verifies it did not change
+const (
+	selectorContent      = ".entry-content"
+	selectorTables       = selectorContent + " table"
+	selectorSearchResult = selectorContent + " ol li"
+	selectorMasteries    = "tr td ol li"
+	headingStoryline     = "Storyline"
+	headingSkills        = "Skills"
+	headingEquipment     = "Equipment Guide"
+	headingMasteries     = "Mastery Guide"
+)
+
 func (c *Command) requestUrl(url string) (*goquery.Document, error) {
 	req, errRequest := http.NewRequest("GET", url, nil)
 	if errRequest != nil {
@@ -66,30 +80,31 @@ func (c *Command) requestUrl(url string) (*goquery.Document, error) {
 	return goquery.NewDocumentFromReader(resp.Body)
 }
 
-func (c *Command) getDoc(champion *common.Champion) (*goquery.Document, error) {
-	slugToLookup := champion.Slug
-	if v, ok := slugTranslation[slugToLookup]; ok {
-		slugToLookup = v
-	}
-	doc, errDoc := c.requestUrl(fmt.Sprintf("https://ayumilove.net/raid-shadow-legends-%s-skill-mastery-equip-guide/", slugToLookup))
+func championUrl(champion *common.Champion) string {
+	return fmt.Sprintf("https://ayumilove.net/raid-shadow-legends-%s-skill-mastery-equip-guide/", common.SourceSlug(common.ProvenanceSource_Ayumilove, champion.Slug))
+}
+
+func (c *Command) getDoc(champion *common.Champion) (*goquery.Document, string, error) {
+	docUrl := championUrl(champion)
+	doc, errDoc := c.requestUrl(docUrl)
 	if errDoc != nil {
 		if errDoc == ErrNotFound {
 			doc, errDoc = c.requestUrl(fmt.Sprintf("https://ayumilove.net/?s=%s", url.PathEscape(champion.Name)))
 			if errDoc != nil {
-				return nil, errDoc
+				return nil, "", errDoc
 			}
-			sel := doc.Find(".entry-content ol li").First()
+			sel := doc.Find(selectorSearchResult).First()
 			if sel == nil {
-				return nil, fmt.Errorf("champion not found in search")
+				return nil, "", fmt.Errorf("champion not found in search")
 			}
 			if !strings.Contains(sel.Text(), champion.Name) {
-				return nil, fmt.Errorf("champion not found in search")
+				return nil, "", fmt.Errorf("champion not found in search")
 			}
-			href, _ := sel.Find("a").First().Attr("href")
-			doc, errDoc = c.requestUrl(href)
+			docUrl, _ = sel.Find("a").First().Attr("href")
+			doc, errDoc = c.requestUrl(docUrl)
 		}
 	}
-	return doc, errDoc
+	return doc, docUrl, errDoc
 }
 
 func (c *Command) Run() {
@@ -104,10 +119,16 @@ func (c *Command) Run() {
 		utils.Exit(1, fmt.Errorf("found %d champions with slug %s", len(champions), *c.ChampionSlug))
 	}
 	champion := champions[0]
-	doc, errDoc := c.getDoc(champion)
+	merger, errMerger := common.NewMerger(*c.MergePolicy, *c.ReviewQueue)
+	if errMerger != nil {
+		utils.Exit(1, errMerger)
+	}
+	c.merger = merger
+	doc, docUrl, errDoc := c.getDoc(champion)
 	if errDoc != nil {
 		utils.Exit(1, errDoc)
 	}
+	c.docUrl = docUrl
 	if c.Builds != nil && *c.Builds {
 		// don't keep ayumilove's builds
 		builds := []*common.Build{}
@@ -118,6 +139,7 @@ func (c *Command) Run() {
 		}
 		champion.RecommendedBuilds = builds
 		c.parseEquipment(champion, doc)
+		champion.SetProvenance("recommended_builds", common.ProvenanceSource_Ayumilove, c.docUrl)
 	}
 	if c.Masteries != nil && *c.Masteries {
 		// don't keep ayumilove's masteries
@@ -129,6 +151,7 @@ func (c *Command) Run() {
 		}
 		champion.Masteries = masteries
 		c.parseMasteries(champion, doc)
+		champion.SetProvenance("masteries", common.ProvenanceSource_Ayumilove, c.docUrl)
 	}
 	if c.Stats != nil && *c.Stats {
 		c.parseStats(champion, doc)
@@ -151,10 +174,14 @@ func (c *Command) Run() {
 	if errWrite != nil {
 		utils.Exit(1, errWrite)
 	}
+	errQueue := c.merger.Queue.Save()
+	if errQueue != nil {
+		utils.Exit(1, errQueue)
+	}
 }
 
 func (c *Command) parseStoryline(champion *common.Champion, doc *goquery.Document) {
-	doc.Find(".entry-content").Each(func(_ int, s *goquery.Selection) {
+	doc.Find(selectorContent).Each(func(_ int, s *goquery.Selection) {
 		check := 0
 		s.Children().Each(func(_ int, sc *goquery.Selection) {
 			switch check {
@@ -164,9 +191,10 @@ func (c *Command) parseStoryline(champion *common.Champion, doc *goquery.Documen
 					return
 				}
 				champion.Lore = fmt.Sprintf("<p>%s</p>", sc.Text())
+				champion.SetProvenance("lore", common.ProvenanceSource_Ayumilove, c.docUrl)
 			default:
 				check = 0
-				if strings.HasSuffix(sc.Text(), "Storyline") {
+				if strings.HasSuffix(sc.Text(), headingStoryline) {
 					check = 1
 				}
 			}
@@ -179,7 +207,7 @@ var (
 )
 
 func (c *Command) parseStats(champion *common.Champion, doc *goquery.Document) {
-	doc.Find(".entry-content table").Each(func(idx int, s *goquery.Selection) {
+	doc.Find(selectorTables).Each(func(idx int, s *goquery.Selection) {
 		if idx != 0 {
 			// only the first index is interesting for stats
 			return
@@ -239,7 +267,9 @@ func (c *Command) parseStats(champion *common.Champion, doc *goquery.Document) {
 						*floatField = float64(v) / 100.0
 					}
 				}
-				champion.Characteristics[60] = chars
+				if c.merger.Accept(champion, "characteristics.60", common.ProvenanceSource_Ayumilove, c.docUrl, champion.Characteristics[60], chars) {
+					champion.Characteristics[60] = chars
+				}
 			})
 		})
 	})
@@ -260,7 +290,7 @@ var (
 func (c *Command) parseRating(champion *common.Champion, doc *goquery.Document) {
 	rating := &common.Rating{}
 	found := false
-	doc.Find(".entry-content table").Each(func(idx int, s *goquery.Selection) {
+	doc.Find(selectorTables).Each(func(idx int, s *goquery.Selection) {
 		if idx > 1 || found {
 			// only the first and second index is interesting for stats
 			// if already found, then stop treating
@@ -313,6 +343,7 @@ func (c *Command) parseRating(champion *common.Champion, doc *goquery.Document)
 		})
 	})
 	champion.AddRating("ayumilove", rating, 2)
+	champion.SetProvenance(common.RatingSourceProvenanceField("ayumilove"), common.ProvenanceSource_Ayumilove, c.docUrl)
 }
 
 var (
@@ -324,12 +355,12 @@ var (
 
 func (c *Command) parseEquipment(champion *common.Champion, doc *goquery.Document) {
 	equipmentContent := []string{}
-	doc.Find(".entry-content").Each(func(_ int, s *goquery.Selection) {
+	doc.Find(selectorContent).Each(func(_ int, s *goquery.Selection) {
 		check := 0
 		s.Children().Each(func(_ int, sc *goquery.Selection) {
 			switch check {
 			case 1:
-				if sc.Is("h2") || strings.HasSuffix(sc.Text(), "Mastery Guide") {
+				if sc.Is("h2") || strings.HasSuffix(sc.Text(), headingMasteries) {
 					check = 2
 				} else {
 					if text := strings.Trim(sc.Text(), " "); text != "" {
@@ -343,9 +374,9 @@ func (c *Command) parseEquipment(champion *common.Champion, doc *goquery.Documen
 				}
 			default:
 				check = 0
-				if strings.HasSuffix(sc.Text(), "Equipment Guide") {
+				if strings.HasSuffix(sc.Text(), headingEquipment) {
 					check = 1
-				} else if strings.HasSuffix(sc.Text(), "Mastery Guide") {
+				} else if strings.HasSuffix(sc.Text(), headingMasteries) {
 					check = 2
 				}
 			}
@@ -391,7 +422,7 @@ var (
 
 func (c *Command) parseSkills(champion *common.Champion, doc *goquery.Document) {
 	skillNumber := 1
-	doc.Find(".entry-content").Each(func(_ int, s *goquery.Selection) {
+	doc.Find(selectorContent).Each(func(_ int, s *goquery.Selection) {
 		check := 0
 		s.Children().Each(func(_ int, sc *goquery.Selection) {
 			switch check {
@@ -419,6 +450,13 @@ func (c *Command) parseSkills(champion *common.Champion, doc *goquery.Document)
 				description = strings.TrimSpace(description)
 				if skillName != "Aura" {
 					passive := strings.Contains(data[0], "[Passive]")
+					currentDescription := ""
+					if skill, errSkill := champion.GetSkillByName(skillName); errSkill == nil {
+						currentDescription = skill.RawDescription
+					}
+					if !c.merger.Accept(champion, common.SkillProvenanceField(skillName, "raw_description"), common.ProvenanceSource_Ayumilove, c.docUrl, currentDescription, description) {
+						description = currentDescription
+					}
 					skill := champion.AddSkill(skillName, description, passive)
 					currentSkillNumber := 0
 					for idx := range champion.Skills {
@@ -435,7 +473,7 @@ func (c *Command) parseSkills(champion *common.Champion, doc *goquery.Document)
 						if len(cooldown) == 1 {
 							if intV, err := strconv.ParseInt(cooldown[0][1], 10, 64); err != nil {
 								utils.Exit(1, err)
-							} else {
+							} else if c.merger.Accept(champion, common.SkillProvenanceField(skillName, "cooldown"), common.ProvenanceSource_Ayumilove, c.docUrl, skill.Cooldown, intV) {
 								skill.Cooldown = intV
 							}
 						}
@@ -443,10 +481,11 @@ func (c *Command) parseSkills(champion *common.Champion, doc *goquery.Document)
 					skillNumber++
 				} else {
 					champion.SetAura(description)
+					champion.SetProvenance("auras", common.ProvenanceSource_Ayumilove, c.docUrl)
 				}
 			default:
 				check = 0
-				if strings.HasSuffix(sc.Text(), "Skills") {
+				if strings.HasSuffix(sc.Text(), headingSkills) {
 					check = 1
 				}
 			}
@@ -456,14 +495,14 @@ func (c *Command) parseSkills(champion *common.Champion, doc *goquery.Document)
 
 func (c *Command) parseMasteries(champion *common.Champion, doc *goquery.Document) {
 	content := []string{}
-	doc.Find(".entry-content").Each(func(_ int, s *goquery.Selection) {
+	doc.Find(selectorContent).Each(func(_ int, s *goquery.Selection) {
 		check := 0
 		s.Children().Each(func(_ int, sc *goquery.Selection) {
 			switch check {
 			case 2:
 				// mastery guide
 				if sc.Is("table") {
-					sc.Find("tr td ol li").Each(func(_ int, mastery *goquery.Selection) {
+					sc.Find(selectorMasteries).Each(func(_ int, mastery *goquery.Selection) {
 						content = append(content, fmt.Sprintf("mastery: %s", mastery.Text()))
 					})
 				} else if sc.Is("h2") {
@@ -474,7 +513,7 @@ func (c *Command) parseMasteries(champion *common.Champion, doc *goquery.Documen
 				}
 			default:
 				check = 0
-				if strings.HasSuffix(sc.Text(), "Mastery Guide") {
+				if strings.HasSuffix(sc.Text(), headingMasteries) {
 					check = 2
 				}
 			}
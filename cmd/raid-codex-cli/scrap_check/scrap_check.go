@@ -0,0 +1,93 @@
+package scrap_check
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/raid-codex/tools/cmd/raid-codex-cli/champions_parse_tierlist_hellhades"
+	"github.com/raid-codex/tools/cmd/raid-codex-cli/parse_full_sheet"
+	"github.com/raid-codex/tools/cmd/raid-codex-cli/scrap_ayumilove_champions"
+	"github.com/raid-codex/tools/cmd/raid-codex-cli/scrap_gameronion_champions"
+	"github.com/raid-codex/tools/cmd/raid-codex-cli/scrap_raidshadowlegendspro_champions"
+	"github.com/raid-codex/tools/cmd/raid-codex-cli/scrap_wikia_characteristics"
+	"github.com/raid-codex/tools/common"
+	"github.com/raid-codex/tools/utils"
+	"github.com/raid-codex/tools/utils/scrapcheck"
+	"gopkg.in/alecthomas/kingpin.v2"
+)
+
+type Command struct {
+	DataDirectory  *string
+	ChampionSlug   *string
+	CacheDirectory *string
+	Fresh          *bool
+	WikiaCSVFile   *string
+	FullSheets     *map[string]string
+}
+
+func New(cmd *kingpin.CmdClause) *Command {
+	return &Command{
+		DataDirectory:  cmd.Flag("data-directory", "Directory containing data").Required().String(),
+		ChampionSlug:   cmd.Flag("champion-slug", "Champion whose pages are used to check per-champion scrapers").Default("kael").String(),
+		CacheDirectory: cmd.Flag("cache-directory", "Directory in which fetched pages are cached").String(),
+		Fresh:          cmd.Flag("fresh", "Fetch pages even if they are cached").Bool(),
+		WikiaCSVFile:   cmd.Flag("wikia-csv-file", "CSV file converted from the wikia tier list, to check it before scrap wikia-characteristics").String(),
+		FullSheets:     cmd.Flag("full-sheet", "Full sheet exported as CSV, to check it before parse full-sheet, e.g. detail=detail.csv").StringMap(),
+	}
+}
+
+func (c *Command) Run() {
+	errFactory := common.InitFactory(*c.DataDirectory)
+	if errFactory != nil {
+		utils.Exit(1, errFactory)
+	}
+	champions, errChampions := common.GetChampions(common.FilterChampionSlug(*c.ChampionSlug))
+	if errChampions != nil {
+		utils.Exit(1, errChampions)
+	} else if len(champions) != 1 {
+		utils.Exit(1, fmt.Errorf("found %d champions with slug %s", len(champions), *c.ChampionSlug))
+	}
+	champion := champions[0]
+	checks := []*scrapcheck.Check{
+		scrap_ayumilove_champions.HealthCheck(champion),
+		scrap_gameronion_champions.HealthCheck(champion),
+		scrap_raidshadowlegendspro_champions.HealthCheck(champion),
+		champions_parse_tierlist_hellhades.HealthCheck(),
+		scrap_wikia_characteristics.HealthCheck(),
+	}
+	if *c.WikiaCSVFile != "" {
+		checks = append(checks, scrap_wikia_characteristics.SheetHealthCheck(*c.WikiaCSVFile))
+	}
+	for sheetType, file := range *c.FullSheets {
+		check, errCheck := parse_full_sheet.SheetHealthCheck(sheetType, file)
+		if errCheck != nil {
+			utils.Exit(1, errCheck)
+		}
+		checks = append(checks, check)
+	}
+	fetcher := &scrapcheck.Fetcher{CacheDirectory: *c.CacheDirectory, Fresh: *c.Fresh}
+	broken := 0
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "SCRAPER\tSTATUS\tDETAILS\n")
+	for _, check := range checks {
+		failures, errRun := check.Run(fetcher)
+		if errRun != nil {
+			broken++
+			fmt.Fprintf(w, "%s\tERROR\t%s\n", check.Scraper, errRun)
+			continue
+		}
+		if len(failures) == 0 {
+			fmt.Fprintf(w, "%s\tOK\t%s\n", check.Scraper, check.URL)
+			continue
+		}
+		broken++
+		for _, failure := range failures {
+			fmt.Fprintf(w, "%s\tBROKEN\t%s: %s\n", check.Scraper, failure.Expectation.Description, failure.Reason)
+		}
+	}
+	w.Flush()
+	if broken > 0 {
+		utils.Exit(1, fmt.Errorf("%d scrapers out of %d are broken", broken, len(checks)))
+	}
+}
@@ -0,0 +1,20 @@
+package scrap_gameronion_champions
+
+import (
+	"github.com/raid-codex/tools/common"
+	"github.com/raid-codex/tools/utils/scrapcheck"
+)
+
+// HealthCheck lists what the parser of this package expects from the page of champion
+func HealthCheck(champion *common.Champion) *scrapcheck.Check {
+	return &scrapcheck.Check{
+		Scraper: "gameronion champions",
+		URL:     championUrl(champion),
+		Expectations: []scrapcheck.Expectation{
+			{Description: "faction in 2nd <p>", Selector: selectorFaction, MinCount: 2},
+			{Description: "rarity", Selector: selectorRarity},
+			{Description: "type", Selector: selectorType},
+			{Description: "skills", Selector: selectorSkills, Contains: markerSkillLevel},
+		},
+	}
+}
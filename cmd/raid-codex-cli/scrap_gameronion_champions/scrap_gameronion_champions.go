@@ -29,19 +29,21 @@ func New(cmd *kingpin.CmdClause) *Command {
 	}
 }
 
+func championUrl(champion *common.Champion) string {
+	return fmt.Sprintf("https://www.gameronion.com/Raid-Shadow-Legends/champions/%s", champion.Slug)
+}
+
 func (c *Command) Run() {
 	errFactory := common.InitFactory(*c.DataDirectory)
 	if errFactory != nil {
 		utils.Exit(1, errFactory)
 	}
-	champions, errChampions := common.GetChampions(common.FilterChampionName(*c.ChampionName))
-	if errChampions != nil {
-		utils.Exit(1, errChampions)
-	} else if len(champions) != 1 {
-		utils.Exit(1, fmt.Errorf("found %d champions with name %s", len(champions), *c.ChampionName))
+	champion, errChampion := common.FindChampion(*c.ChampionName)
+	if errChampion != nil {
+		utils.Exit(1, errChampion)
 	}
-	champion := champions[0]
-	req, errRequest := http.NewRequest("GET", fmt.Sprintf("https://www.gameronion.com/Raid-Shadow-Legends/champions/%s", champion.Slug), nil)
+	pageUrl := championUrl(champion)
+	req, errRequest := http.NewRequest("GET", pageUrl, nil)
 	if errRequest != nil {
 		utils.Exit(1, errRequest)
 	}
@@ -57,19 +59,19 @@ func (c *Command) Run() {
 	if errDoc != nil {
 		utils.Exit(1, errDoc)
 	}
-	doc.Find(".faction p").Each(func(i int, s *goquery.Selection) {
+	doc.Find(selectorFaction).Each(func(i int, s *goquery.Selection) {
 		if i == 1 {
 			faction := strings.Trim(s.Find("a").Text(), " \n")
 			log.Printf("Faction: %s\n", faction)
 		}
 	})
-	doc.Find(".rarity").Each(func(i int, s *goquery.Selection) {
+	doc.Find(selectorRarity).Each(func(i int, s *goquery.Selection) {
 		log.Printf("Rarity: %s\n", strings.Trim(s.Text(), " \n"))
 	})
-	doc.Find(".champtype").Each(func(i int, s *goquery.Selection) {
+	doc.Find(selectorType).Each(func(i int, s *goquery.Selection) {
 		log.Printf("Type: %s\n", strings.Trim(s.Text(), " \n"))
 	})
-	doc.Find(".skill-cont").Each(func(i int, s *goquery.Selection) {
+	doc.Find(selectorSkills).Each(func(i int, s *goquery.Selection) {
 		txt := s.Text()
 		// remove double \n + double spaces
 		txt = emptyLinesRegexp.ReplaceAllString(txt, "")
@@ -90,7 +92,7 @@ func (c *Command) Run() {
 			switch true {
 			case strings.HasPrefix(part, "[Passive]"):
 				passive = true
-			case strings.HasPrefix(part, "Level "):
+			case strings.HasPrefix(part, markerSkillLevel):
 				continue
 			case strings.HasPrefix(part, "Cooldown: "):
 				pCooldown, errCooldown := strconv.ParseInt(strings.Trim(part[9:], " "), 10, 64)
@@ -148,6 +150,7 @@ func (c *Command) Run() {
 					RawDescription: skillDescription,
 				},
 			}
+			champion.SetProvenance("auras", common.ProvenanceSource_Gameronion, pageUrl)
 		}
 	})
 	errSanitize := champion.Sanitize()
@@ -170,6 +173,15 @@ var (
 	emptyLinesRegexp = regexp.MustCompile(`(?m)^(?:[\t ]*(?:\r?\n|\r))+`)
 )
 
+// Layout of the pages the parser relies on, HealthCheck verifies it did not change
+const (
+	selectorFaction  = ".faction p"
+	selectorRarity   = ".rarity"
+	selectorType     = ".champtype"
+	selectorSkills   = ".skill-cont"
+	markerSkillLevel = "Level "
+)
+
 func printDiff(a []byte, b []byte) {
 	aa := map[string]interface{}{}
 	bb := map[string]interface{}{}
@@ -0,0 +1,19 @@
+package scrap_raidshadowlegendspro_champions
+
+import (
+	"github.com/raid-codex/tools/common"
+	"github.com/raid-codex/tools/utils/scrapcheck"
+)
+
+// HealthCheck lists what the parsers of this package expect from the page of champion
+func HealthCheck(champion *common.Champion) *scrapcheck.Check {
+	return &scrapcheck.Check{
+		Scraper: "raidshadowlegends.pro champions",
+		URL:     championUrl(champion),
+		Expectations: []scrapcheck.Expectation{
+			{Description: "skill names", Selector: selectorContent + " " + selectorSkillNames, Regexp: skillNameRpl},
+			{Description: "skills heading", Selector: selectorContent + " > *", HasSuffix: headingSkills},
+			{Description: "skill levels", Selector: selectorContent + " > *", Contains: markerSkillLevel},
+		},
+	}
+}
@@ -17,6 +17,9 @@ type Command struct {
 	ChampionName  *string
 	DataDirectory *string
 	Skills        *bool
+	MergePolicy   *string
+	ReviewQueue   *string
+	merger        *common.Merger
 }
 
 func New(cmd *kingpin.CmdClause) *Command {
@@ -24,6 +27,8 @@ func New(cmd *kingpin.CmdClause) *Command {
 		DataDirectory: cmd.Flag("data-directory", "Directory containing data").Required().String(),
 		ChampionName:  cmd.Flag("champion-name", "Name of the champion being looked up").Required().String(),
 		Skills:        cmd.Flag("with-skills", "Fetch champion skills and store them").Bool(),
+		MergePolicy:   cmd.Flag("merge-policy", "JSON file with per-field source priorities, built-in priorities are used when not set").String(),
+		ReviewQueue:   cmd.Flag("review-queue", "JSON file in which conflicting values are queued for review").Default("review-queue.json").String(),
 	}
 }
 
@@ -44,21 +49,29 @@ func (c *Command) getFromWebsite(url string) (*http.Response, error) {
 	return resp, nil
 }
 
+func championUrl(champion *common.Champion) string {
+	return fmt.Sprintf("https://raidshadowlegends.pro/%s/raid-shadow-legends-%s-build-guide/", champion.FactionSlug, champion.Slug)
+}
+
 func (c *Command) Run() {
 	errFactory := common.InitFactory(*c.DataDirectory)
 	if errFactory != nil {
 		utils.Exit(1, errFactory)
 	}
-	champions, errChampions := common.GetChampions(common.FilterChampionName(*c.ChampionName))
-	if errChampions != nil {
-		utils.Exit(1, errChampions)
-	} else if len(champions) != 1 {
-		utils.Exit(1, fmt.Errorf("found %d champions with name %s", len(champions), *c.ChampionName))
+	champion, errChampion := common.FindChampion(*c.ChampionName)
+	if errChampion != nil {
+		utils.Exit(1, errChampion)
+	}
+	merger, errMerger := common.NewMerger(*c.MergePolicy, *c.ReviewQueue)
+	if errMerger != nil {
+		utils.Exit(1, errMerger)
 	}
-	champion := champions[0]
-	resp, errReq := c.getFromWebsite(fmt.Sprintf("https://raidshadowlegends.pro/%s/raid-shadow-legends-%s-build-guide/", champion.FactionSlug, champion.Slug))
+	c.merger = merger
+	pageUrl := championUrl(champion)
+	resp, errReq := c.getFromWebsite(pageUrl)
 	if errReq != nil {
-		resp, errReq = c.getFromWebsite(fmt.Sprintf("https://raidshadowlegends.pro/%s/%s/", champion.FactionSlug, champion.Slug))
+		pageUrl = fmt.Sprintf("https://raidshadowlegends.pro/%s/%s/", champion.FactionSlug, champion.Slug)
+		resp, errReq = c.getFromWebsite(pageUrl)
 	}
 	if errReq != nil {
 		utils.Exit(1, errReq)
@@ -69,7 +82,7 @@ func (c *Command) Run() {
 		utils.Exit(1, errDoc)
 	}
 	if c.Skills != nil && *c.Skills {
-		c.parseSkills(champion, doc)
+		c.parseSkills(champion, doc, pageUrl)
 	}
 	errSanitize := champion.Sanitize()
 	if errSanitize != nil {
@@ -80,8 +93,20 @@ func (c *Command) Run() {
 	if errWrite != nil {
 		utils.Exit(1, errWrite)
 	}
+	errQueue := c.merger.Queue.Save()
+	if errQueue != nil {
+		utils.Exit(1, errQueue)
+	}
 }
 
+// Layout of the pages the parser relies on, HealthCheck verifies it did not change
+const (
+	selectorContent    = ".entry-content"
+	selectorSkillNames = "h3"
+	headingSkills      = "Skills"
+	markerSkillLevel   = "Lvl."
+)
+
 var (
 	skillNameRpl = regexp.MustCompile("^((.+) Level 1|(Aura))$")
 	skipEffects  = regexp.MustCompile("<strong>(.+)</strong>")
@@ -126,12 +151,12 @@ func sanitizeString(str string) string {
 	return str
 }
 
-func (c *Command) parseSkills(champion *common.Champion, doc *goquery.Document) {
-	doc.Find(".entry-content").Each(func(_ int, s *goquery.Selection) {
+func (c *Command) parseSkills(champion *common.Champion, doc *goquery.Document, pageUrl string) {
+	doc.Find(selectorContent).Each(func(_ int, s *goquery.Selection) {
 		skills := false
 		// skils name have h3
 		skillNames := map[string]bool{}
-		s.Find("h3").Each(func(_ int, sc *goquery.Selection) {
+		s.Find(selectorSkillNames).Each(func(_ int, sc *goquery.Selection) {
 			skillName := strings.Trim(sanitizeString(sc.Text()), " ")
 			m := skillNameRpl.FindStringSubmatch(skillName)
 			if m == nil {
@@ -145,7 +170,7 @@ func (c *Command) parseSkills(champion *common.Champion, doc *goquery.Document)
 		currentSkill := ""
 		s.Children().Each(func(_ int, sc *goquery.Selection) {
 			skillData := sanitizeString(sc.Text())
-			if !skills && strings.HasSuffix(skillData, "Skills") {
+			if !skills && strings.HasSuffix(skillData, headingSkills) {
 				skills = true
 			} else if skills {
 				if currentSkill == "" {
@@ -167,7 +192,7 @@ func (c *Command) parseSkills(champion *common.Champion, doc *goquery.Document)
 						}
 						skillMap[currentSkill] += html
 					}
-					if strings.Contains(skillData, "Lvl.") || currentSkill == "Aura" {
+					if strings.Contains(skillData, markerSkillLevel) || currentSkill == "Aura" {
 						// reset
 						currentSkill = ""
 					}
@@ -181,6 +206,7 @@ func (c *Command) parseSkills(champion *common.Champion, doc *goquery.Document)
 						RawDescription: strings.ToUpper(skillDescription[0:1]) + skillDescription[1:],
 					},
 				}
+				champion.SetProvenance("auras", common.ProvenanceSource_RaidShadowLegendsPro, pageUrl)
 			} else {
 				passive := false
 				if strings.Contains(skillName, "[P]") {
@@ -203,7 +229,7 @@ func (c *Command) parseSkills(champion *common.Champion, doc *goquery.Document)
 				}
 				skill.Passive = passive
 				skill.Name = skillName
-				if skillDescription != "" {
+				if skillDescription != "" && c.merger.Accept(champion, common.SkillProvenanceField(skillName, "raw_description"), common.ProvenanceSource_RaidShadowLegendsPro, pageUrl, skill.RawDescription, skillDescription) {
 					skill.RawDescription = skillDescription
 				}
 			}
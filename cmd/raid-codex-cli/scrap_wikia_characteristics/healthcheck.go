@@ -0,0 +1,28 @@
+package scrap_wikia_characteristics
+
+import (
+	"strings"
+
+	"github.com/raid-codex/tools/utils/scrapcheck"
+)
+
+// HealthCheck lists what the parser of this package expects from the tier list, whose table is
+// converted to the CSV file the parser reads
+func HealthCheck() *scrapcheck.Check {
+	return &scrapcheck.Check{
+		Scraper: "wikia characteristics",
+		URL:     sourceUrl,
+		Expectations: []scrapcheck.Expectation{
+			{Description: "columns of the table", Selector: "table th", Texts: strings.Split(csvSafeguardOrder, ",")},
+		},
+	}
+}
+
+// SheetHealthCheck checks that the CSV file converted from the tier list can be parsed
+func SheetHealthCheck(file string) *scrapcheck.Check {
+	return &scrapcheck.Check{
+		Scraper: "wikia characteristics sheet",
+		URL:     file,
+		Header:  checkHeader,
+	}
+}
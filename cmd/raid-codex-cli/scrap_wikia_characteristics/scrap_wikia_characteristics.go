@@ -4,6 +4,7 @@ import (
 	"encoding/csv"
 	"encoding/json"
 	"fmt"
+	"io/ioutil"
 	"os"
 	"strconv"
 	"strings"
@@ -18,16 +19,24 @@ import (
 type Command struct {
 	CSVFile *string
 	ChampionsFolder *string
+	MergePolicy     *string
+	ReviewQueue     *string
 }
 
 func New(cmd *kingpin.CmdClause) *Command {
 	return &Command{
 		CSVFile: cmd.Flag("csv-file", "CSV File with champions list and characteristics").Required().String(),
 		ChampionsFolder: cmd.Flag("champions-folder", "Folder in which current champions are stored. JSON files will be edited in-place").Required().String(),
+		MergePolicy:     cmd.Flag("merge-policy", "JSON file with per-field source priorities, built-in priorities are used when not set").String(),
+		ReviewQueue:     cmd.Flag("review-queue", "JSON file in which conflicting values are queued for review").Default("review-queue.json").String(),
 	}
 }
 
 func (c *Command) Run() {
+	merger, errMerger := common.NewMerger(*c.MergePolicy, *c.ReviewQueue)
+	if errMerger != nil {
+		utils.Exit(1, errMerger)
+	}
 	file, errFile := os.Open(*c.CSVFile)
 	if errFile != nil {
 		utils.Exit(1, errFile)
@@ -38,10 +47,18 @@ func (c *Command) Run() {
 	if errRead != nil {
 		utils.Exit(1, errRead)
 	}
+	champions, errChampions := c.readChampions()
+	if errChampions != nil {
+		utils.Exit(1, errChampions)
+	}
+	names := make([]string, 0, len(champions))
+	for name := range champions {
+		names = append(names, name)
+	}
 	for idx, line := range content {
 		if idx == 0 {
-			if strings.Join(line, ",") != csvSafeguardOrder {
-				utils.Exit(1, fmt.Errorf("invalid csv"))
+			if errHeader := checkHeader(line); errHeader != nil {
+				utils.Exit(1, errHeader)
 			}
 			// skip first line, it's the header
 			continue
@@ -49,10 +66,11 @@ func (c *Command) Run() {
 		if len(line) != 18 {
 			utils.Exit(1, fmt.Errorf("line %s has %d parts, not 18", strings.Join(line, ","), len(line)))
 		}
-		champion, err := c.getChampionByName(line[1])
-		if err != nil {
-			utils.Exit(1, err)
+		name, found := common.MatchName(line[1], names)
+		if !found {
+			utils.Exit(1, common.NewChampionNotFoundError(line[1], names))
 		}
+		champion := champions[name]
 		characteristics := champion.Characteristics[60]
 		characteristics.HP = mustInt64(line[5])
 		characteristics.Attack = mustInt64(line[6])
@@ -62,12 +80,25 @@ func (c *Command) Run() {
 		characteristics.CriticalDamage = float64(mustInt64(line[10][0:len(line[10])-1])) / 100.0
 		characteristics.Resistance = mustInt64(line[11])
 		characteristics.Accuracy = mustInt64(line[12])
-		champion.Characteristics[60] = characteristics
+		if merger.Accept(champion, "characteristics.60", common.ProvenanceSource_Wikia, sourceUrl, champion.Characteristics[60], characteristics) {
+			champion.Characteristics[60] = characteristics
+		}
 		errWrite := utils.WriteToFile(fmt.Sprintf("%s/%s", *c.ChampionsFolder, champion.Filename()), champion)
 		if errWrite != nil {
 			utils.Exit(1, errWrite)
 		}
 	}
+	errQueue := merger.Queue.Save()
+	if errQueue != nil {
+		utils.Exit(1, errQueue)
+	}
+}
+
+func checkHeader(line []string) error {
+	if strings.Join(line, ",") != csvSafeguardOrder {
+		return fmt.Errorf("invalid csv, first line must be %s", csvSafeguardOrder)
+	}
+	return nil
 }
 
 func mustInt64(str string) int64 {
@@ -78,25 +109,35 @@ func mustInt64(str string) int64 {
 	return v
 }
 
-func (c *Command) getChampionByName(name string) (*common.Champion, error) {
-	nameOk, errSanitize := common.GetSanitizedName(name)
-	if errSanitize != nil {
-		return nil, errSanitize
+// readChampions reads the champions of the champions folder, by name
+func (c *Command) readChampions() (map[string]*common.Champion, error) {
+	files, errDir := ioutil.ReadDir(*c.ChampionsFolder)
+	if errDir != nil {
+		return nil, errDir
 	}
-	file, errOpen := os.Open(fmt.Sprintf("%s/%s.json", *c.ChampionsFolder, common.GetLinkNameFromSanitizedName(nameOk)))
-	if errOpen != nil {
-		return nil, errOpen
-	} else {
-		defer file.Close()
+	champions := map[string]*common.Champion{}
+	for _, file := range files {
+		if file.Name() == "index.json" || !strings.HasSuffix(file.Name(), ".json") {
+			continue
+		}
 		var champion common.Champion
-		errJSON := json.NewDecoder(file).Decode(&champion)
-		if errJSON != nil {
-			return nil, errJSON
+		errRead := func() error {
+			f, errOpen := os.Open(fmt.Sprintf("%s/%s", *c.ChampionsFolder, file.Name()))
+			if errOpen != nil {
+				return errOpen
+			}
+			defer f.Close()
+			return json.NewDecoder(f).Decode(&champion)
+		}()
+		if errRead != nil {
+			return nil, errRead
 		}
-		return &champion, nil
+		champions[champion.Name] = &champion
 	}
+	return champions, nil
 }
 
 const (
+	sourceUrl         = "https://raid-shadow-legends.fandom.com/wiki/Tier_List"
 	csvSafeguardOrder = `Rarity,Element,Tribe,Role,Name,HP,ATK,DEF,SPD,C-Rate,C-Dmg,RES,ACC,Skill1,Skill2,Skill3,Skill4,Aura`
 )
\ No newline at end of file
@@ -0,0 +1,35 @@
+package search_export
+
+import (
+	"github.com/raid-codex/tools/common"
+	"github.com/raid-codex/tools/search"
+	"github.com/raid-codex/tools/utils"
+	"gopkg.in/alecthomas/kingpin.v2"
+)
+
+type Command struct {
+	DataDirectory *string
+	Output        *string
+}
+
+func New(cmd *kingpin.CmdClause) *Command {
+	return &Command{
+		DataDirectory: cmd.Flag("data-directory", "Data directory").Required().String(),
+		Output:        cmd.Flag("output", "JSON file receiving the index, terms map to [document, weight] postings").Default("search-index.json").String(),
+	}
+}
+
+func (c *Command) Run() {
+	errFactory := common.InitFactory(*c.DataDirectory)
+	if errFactory != nil {
+		utils.Exit(1, errFactory)
+	}
+	index, errIndex := search.FromFactory()
+	if errIndex != nil {
+		utils.Exit(1, errIndex)
+	}
+	errWrite := utils.WriteToFile(*c.Output, index)
+	if errWrite != nil {
+		utils.Exit(1, errWrite)
+	}
+}
@@ -1,17 +1,21 @@
 package server_run
 
 import (
-	"bytes"
-	"encoding/json"
-	"fmt"
-	"html/template"
-	"io/ioutil"
+	"context"
 	"log"
+	"net/http"
 	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/juju/errors"
 	"github.com/raid-codex/tools/common"
+	"github.com/raid-codex/tools/media"
+	"github.com/raid-codex/tools/search"
+	"github.com/raid-codex/tools/site"
 	"github.com/raid-codex/tools/templatefuncs"
 	"github.com/raid-codex/tools/utils"
 	"gopkg.in/alecthomas/kingpin.v2"
@@ -21,6 +25,12 @@ type Command struct {
 	DataDirectory  *string
 	TemplateFolder *string
 	PageTemplate   *string
+	ImageManifest  *string
+	ProbeImages    *bool
+
+	searchIndex *search.Index
+	preview     *site.Preview
+	images      *media.Resolver
 }
 
 func New(cmd *kingpin.CmdClause) *Command {
@@ -28,6 +38,8 @@ func New(cmd *kingpin.CmdClause) *Command {
 		DataDirectory:  cmd.Flag("data-directory", "Directory containing data").Required().String(),
 		TemplateFolder: cmd.Flag("template-folder", "Template folder").Required().String(),
 		PageTemplate:   cmd.Flag("page-template", "Page template file").Required().String(),
+		ImageManifest:  cmd.Flag("image-manifest", "Image manifest written by website media manifest, the images missing from it are replaced by their fallbacks, IMAGE_MANIFEST when not set").String(),
+		ProbeImages:    cmd.Flag("probe-images", "Without a manifest, probe the images over HTTP to replace the missing ones by their fallbacks, the results are cached in IMAGE_CACHE").Bool(),
 	}
 }
 
@@ -36,11 +48,53 @@ func (c *Command) Run() {
 	if errFactory != nil {
 		utils.Exit(1, errFactory)
 	}
+	searchIndex, errIndex := search.FromFactory()
+	if errIndex != nil {
+		utils.Exit(1, errIndex)
+	}
+	c.searchIndex = searchIndex
+	images, errImages := media.ResolverFromEnv(*c.ImageManifest, *c.ProbeImages)
+	if errImages != nil {
+		utils.Exit(1, errImages)
+	}
+	templatefuncs.UseImages(images)
+	c.images = images
+	c.preview = site.NewPreview(*c.DataDirectory, *c.TemplateFolder, *c.PageTemplate, "/web")
 	srv := gin.New()
 	srv.Use(errorHandler)
-	srv.GET("/web/champions/:champion_slug", c.webChampionSlug)
-	if err := srv.Run(":8080"); err != nil {
-		utils.Exit(1, err)
+	srv.GET("/", c.webIndex)
+	srv.GET("/web/", c.webIndex)
+	srv.GET("/web/:section/", c.webSection)
+	srv.GET("/web/:section/:slug", c.webPage)
+	srv.GET("/api/search", c.apiSearch)
+	srv.GET("/api/champions", c.apiChampions)
+	server := &http.Server{Addr: ":8080", Handler: srv}
+	go func() {
+		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			utils.Exit(1, err)
+		}
+	}()
+	// the probes of the images are kept for the next runs, saved periodically and on shutdown
+	go c.saveImages(time.Minute)
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
+	<-stop
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if errShutdown := server.Shutdown(ctx); errShutdown != nil {
+		log.Printf("cannot shut down: %v\n", errShutdown)
+	}
+	if errSave := c.images.Save(); errSave != nil {
+		utils.Exit(1, errSave)
+	}
+}
+
+// saveImages saves the image probes every interval, so that they are not lost on a crash
+func (c *Command) saveImages(interval time.Duration) {
+	for range time.Tick(interval) {
+		if errSave := c.images.Save(); errSave != nil {
+			log.Printf("cannot save image cache: %v\n", errSave)
+		}
 	}
 }
 
@@ -50,72 +104,58 @@ func errorHandler(ctx *gin.Context) {
 	log.Printf("errors: %s\n", detectedErrors)
 }
 
-func (c *Command) webChampionSlug(ctx *gin.Context) {
-	slug := ctx.Param("champion_slug")
-	champion, errChampion := c.getChampion(slug)
-	if errChampion != nil {
-		ctx.AbortWithError(500, errChampion)
-		return
-	}
-	templates, errLoad := c.loadTemplates("champion")
-	if errLoad != nil {
-		ctx.AbortWithError(500, errLoad)
-		return
-	}
-	extraData, errData := champion.GetPageExtraData(*c.DataDirectory)
-	if errData != nil {
-		ctx.AbortWithError(500, errData)
-		return
-	}
-	buf := bytes.NewBufferString("")
-	errTemplate := champion.GetPageContent_Templates(templates, buf, extraData)
-	if errTemplate != nil {
-		ctx.AbortWithError(500, errTemplate)
-		return
-	}
-	pageTemplate, errPageTemplate := ioutil.ReadFile(*c.PageTemplate)
-	if errPageTemplate != nil {
-		ctx.AbortWithError(500, errPageTemplate)
-		return
-	}
-	buf2 := bytes.NewBufferString("")
-	tmpl, errTmpl := template.New("page").Funcs(templatefuncs.FuncMap).Parse(string(pageTemplate))
-	if errTmpl != nil {
-		ctx.AbortWithError(500, errTmpl)
+// webIndex lists every entity with a link to its preview
+func (c *Command) webIndex(ctx *gin.Context) {
+	c.render(ctx, c.preview.Index)
+}
+
+func (c *Command) webSection(ctx *gin.Context) {
+	c.render(ctx, func() ([]byte, error) {
+		return c.preview.List(ctx.Param("section"))
+	})
+}
+
+func (c *Command) webPage(ctx *gin.Context) {
+	c.render(ctx, func() ([]byte, error) {
+		return c.preview.Page(ctx.Param("section"), ctx.Param("slug"))
+	})
+}
+
+func (c *Command) render(ctx *gin.Context, render func() ([]byte, error)) {
+	page, errRender := render()
+	if errors.IsNotFound(errRender) {
+		ctx.AbortWithError(404, errRender)
 		return
-	}
-	errExecute := tmpl.Execute(buf2, map[string]interface{}{"Page": buf.String()})
-	if errExecute != nil {
-		ctx.AbortWithError(500, errExecute)
+	} else if errRender != nil {
+		ctx.AbortWithError(500, errRender)
 		return
 	}
-	ctx.Data(200, "text/html", buf2.Bytes())
+	ctx.Data(200, "text/html; charset=utf-8", page)
 }
 
-func (c *Command) loadTemplates(dir string) (*template.Template, error) {
-	dir = fmt.Sprintf("%s/%s", *c.TemplateFolder, dir)
-	files, errFiles := ioutil.ReadDir(dir)
-	if errFiles != nil {
-		return nil, errFiles
-	}
-	templateFiles := make([]string, 0)
-	for _, file := range files {
-		templateFiles = append(templateFiles, fmt.Sprintf("%s/%s", dir, file.Name()))
+func (c *Command) apiSearch(ctx *gin.Context) {
+	limit, errLimit := strconv.Atoi(ctx.DefaultQuery("limit", "20"))
+	if errLimit != nil {
+		ctx.AbortWithError(400, errors.Annotate(errLimit, "invalid limit"))
+		return
 	}
-	return template.New("main.html").Funcs(templatefuncs.FuncMap).ParseFiles(templateFiles...)
+	ctx.JSON(200, c.searchIndex.Search(ctx.Query("q"), limit))
 }
 
-func (c *Command) getChampion(slug string) (*common.Champion, error) {
-	file, errFile := os.Open(fmt.Sprintf("%s/docs/champions/current/%s.json", *c.DataDirectory, slug))
-	if errFile != nil {
-		return nil, errors.Annotate(errFile, "cannot open file")
+func (c *Command) apiChampions(ctx *gin.Context) {
+	filters := make([]common.ChampionFilter, 0)
+	if where := ctx.Query("where"); where != "" {
+		filter, errFilter := common.ParseChampionFilter(where)
+		if errFilter != nil {
+			ctx.AbortWithError(400, errFilter)
+			return
+		}
+		filters = append(filters, filter)
 	}
-	defer file.Close()
-
-	var champion common.Champion
-	errJSON := json.NewDecoder(file).Decode(&champion)
-	if errJSON != nil {
-		return nil, errors.Annotate(errJSON, "cannot unmarshal file")
+	champions, errChampions := common.GetChampions(filters...)
+	if errChampions != nil {
+		ctx.AbortWithError(500, errChampions)
+		return
 	}
-	return &champion, nil
+	ctx.JSON(200, champions)
 }
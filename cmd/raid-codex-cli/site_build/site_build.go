@@ -0,0 +1,72 @@
+package site_build
+
+import (
+	"fmt"
+	"runtime"
+	"strconv"
+
+	"github.com/raid-codex/tools/common"
+	"github.com/raid-codex/tools/media"
+	"github.com/raid-codex/tools/site"
+	"github.com/raid-codex/tools/templatefuncs"
+	"github.com/raid-codex/tools/utils"
+	"gopkg.in/alecthomas/kingpin.v2"
+)
+
+type Command struct {
+	DataDirectory   *string
+	TemplateFolder  *string
+	PageTemplate    *string
+	OutputDirectory *string
+	AssetsDirectory *string
+	WebsiteRoot     *string
+	Workers         *int
+	ImageManifest   *string
+	ProbeImages     *bool
+}
+
+func New(cmd *kingpin.CmdClause) *Command {
+	return &Command{
+		DataDirectory:   cmd.Flag("data-directory", "Data directory").Required().String(),
+		TemplateFolder:  cmd.Flag("template-folder", "Template folder, with a sub-folder per entity (champion, faction, status-effect, fusion)").Required().String(),
+		PageTemplate:    cmd.Flag("page-template", "Page template file").Required().String(),
+		OutputDirectory: cmd.Flag("output-directory", "Directory receiving the site").Required().String(),
+		AssetsDirectory: cmd.Flag("assets-directory", "Directory copied as is to the site (css, js, images)").String(),
+		WebsiteRoot:     cmd.Flag("website-root", "Prefix of links between pages, e.g. https://raid-codex.com, empty for links relative to the host").String(),
+		Workers:         cmd.Flag("workers", "Number of pages rendered in parallel").Default(strconv.Itoa(runtime.NumCPU())).Int(),
+		ImageManifest:   cmd.Flag("image-manifest", "Image manifest written by website media manifest, the images missing from it are replaced by their fallbacks, IMAGE_MANIFEST when not set").String(),
+		ProbeImages:     cmd.Flag("probe-images", "Without a manifest, probe the images over HTTP to replace the missing ones by their fallbacks, the results are cached in IMAGE_CACHE").Bool(),
+	}
+}
+
+func (c *Command) Run() {
+	errFactory := common.InitFactory(*c.DataDirectory)
+	if errFactory != nil {
+		utils.Exit(1, errFactory)
+	}
+	images, errImages := media.ResolverFromEnv(*c.ImageManifest, *c.ProbeImages)
+	if errImages != nil {
+		utils.Exit(1, errImages)
+	}
+	templatefuncs.UseImages(images)
+	builder := &site.Builder{
+		DataDirectory:   *c.DataDirectory,
+		TemplateFolder:  *c.TemplateFolder,
+		PageTemplate:    *c.PageTemplate,
+		OutputDirectory: *c.OutputDirectory,
+		AssetsDirectory: *c.AssetsDirectory,
+		WebsiteRoot:     *c.WebsiteRoot,
+		Workers:         *c.Workers,
+	}
+	errBuild := builder.Build()
+	if report := images.Report(); report != "" {
+		fmt.Println(report)
+	}
+	errSave := images.Save()
+	if errSave != nil {
+		utils.Exit(1, errSave)
+	}
+	if errBuild != nil {
+		utils.Exit(1, errBuild)
+	}
+}
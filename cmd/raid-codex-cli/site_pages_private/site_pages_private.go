@@ -0,0 +1,38 @@
+package site_pages_private
+
+import (
+	"fmt"
+
+	"github.com/raid-codex/tools/publish"
+	"github.com/raid-codex/tools/site"
+	"github.com/raid-codex/tools/utils"
+	"gopkg.in/alecthomas/kingpin.v2"
+)
+
+type Command struct{}
+
+func New(cmd *kingpin.CmdClause) *Command {
+	return &Command{}
+}
+
+func (c *Command) Run() {
+	publisher, errPublisher := publish.FromEnv()
+	if errPublisher != nil {
+		utils.Exit(1, errPublisher)
+	}
+	count := 0
+	for _, parent := range site.PageParents() {
+		pages, errPages := publisher.ListPages(parent)
+		if errPages != nil {
+			utils.Exit(1, errPages)
+		}
+		for _, page := range pages {
+			if page.Status != publish.Status_Private {
+				continue
+			}
+			fmt.Printf("%s\t%d\t%s\n", page.Slug, page.ID, page.Title)
+			count++
+		}
+	}
+	fmt.Printf("%d private pages\n", count)
+}
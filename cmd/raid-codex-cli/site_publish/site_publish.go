@@ -0,0 +1,106 @@
+package site_publish
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/raid-codex/tools/common"
+	"github.com/raid-codex/tools/media"
+	"github.com/raid-codex/tools/publish"
+	"github.com/raid-codex/tools/site"
+	"github.com/raid-codex/tools/templatefuncs"
+	"github.com/raid-codex/tools/utils"
+	"gopkg.in/alecthomas/kingpin.v2"
+)
+
+type Command struct {
+	DataDirectory  *string
+	TemplateFolder *string
+	Workers        *int
+	Attempts       *int
+	Backoff        *time.Duration
+	Force          *bool
+	Status         *string
+	PublishAt      *string
+	FusionsGoLive  *bool
+	ImageManifest  *string
+	ProbeImages    *bool
+}
+
+func New(cmd *kingpin.CmdClause) *Command {
+	return &Command{
+		DataDirectory:  cmd.Flag("data-directory", "Data directory").Required().String(),
+		TemplateFolder: cmd.Flag("template-folder", "Template folder, with a sub-folder per entity (champion, faction, status-effect, fusion)").Required().String(),
+		Workers:        cmd.Flag("workers", "Number of pages published in parallel").Default("4").Int(),
+		Attempts:       cmd.Flag("attempts", "Number of attempts of each call to the publisher").Default("5").Int(),
+		Backoff:        cmd.Flag("backoff", "Wait after the first failed attempt, doubled after each of the next ones").Default("2s").Duration(),
+		Force:          cmd.Flag("force", "Publish every page, even when its content did not change").Bool(),
+		Status:         cmd.Flag("status", "Status of the pages: private, draft, publish or future, new pages are private and existing ones keep their status when not set").String(),
+		PublishAt:      cmd.Flag("publish-at", "RFC 3339 date the pages go live at, schedules the pages").String(),
+		FusionsGoLive:  cmd.Flag("fusions-go-live", "Schedule the pages of upcoming fusions and of their champions to go live when the fusions start").Bool(),
+		ImageManifest:  cmd.Flag("image-manifest", "Image manifest written by website media manifest, the images missing from it are replaced by their fallbacks, IMAGE_MANIFEST when not set").String(),
+		ProbeImages:    cmd.Flag("probe-images", "Without a manifest, probe the images over HTTP to replace the missing ones by their fallbacks, the results are cached in IMAGE_CACHE").Bool(),
+	}
+}
+
+func (c *Command) Run() {
+	schedule, errSchedule := publish.ParseSchedule(*c.Status, *c.PublishAt)
+	if errSchedule != nil {
+		utils.Exit(1, errSchedule)
+	}
+	publisher, errPublisher := publish.FromEnv()
+	if errPublisher != nil {
+		utils.Exit(1, errPublisher)
+	}
+	errFactory := common.InitFactory(*c.DataDirectory)
+	if errFactory != nil {
+		utils.Exit(1, errFactory)
+	}
+	images, errImages := media.ResolverFromEnv(*c.ImageManifest, *c.ProbeImages)
+	if errImages != nil {
+		utils.Exit(1, errImages)
+	}
+	templatefuncs.UseImages(images)
+	hashes, errHashes := publish.HashStoreFromEnv()
+	if errHashes != nil {
+		utils.Exit(1, errHashes)
+	}
+	if *c.Force {
+		hashes.Reset()
+	}
+	var schedules map[string]publish.Schedule
+	if *c.FusionsGoLive {
+		var errSchedules error
+		schedules, errSchedules = site.FusionSchedules(time.Now())
+		if errSchedules != nil {
+			utils.Exit(1, errSchedules)
+		}
+		for slug, s := range schedules {
+			fmt.Printf("%s: %s\n", slug, s)
+		}
+	}
+	sync := &site.Sync{
+		DataDirectory:  *c.DataDirectory,
+		TemplateFolder: *c.TemplateFolder,
+		Publisher:      publish.WithRetry(publisher, *c.Attempts, *c.Backoff),
+		Hashes:         hashes,
+		Schedule:       schedule,
+		Schedules:      schedules,
+		Workers:        *c.Workers,
+	}
+	report, errSync := sync.Run()
+	if errSync != nil {
+		utils.Exit(1, errSync)
+	}
+	fmt.Println(report)
+	if imagesReport := images.Report(); imagesReport != "" {
+		fmt.Println(imagesReport)
+	}
+	errSave := images.Save()
+	if errSave != nil {
+		utils.Exit(1, errSave)
+	}
+	if len(report.Failed) > 0 {
+		utils.Exit(1, fmt.Errorf("%d pages failed", len(report.Failed)))
+	}
+}
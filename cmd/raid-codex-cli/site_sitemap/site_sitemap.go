@@ -0,0 +1,43 @@
+package site_sitemap
+
+import (
+	"os"
+
+	"github.com/raid-codex/tools/common"
+	"github.com/raid-codex/tools/site"
+	"github.com/raid-codex/tools/utils"
+	"gopkg.in/alecthomas/kingpin.v2"
+)
+
+type Command struct {
+	DataDirectory   *string
+	OutputDirectory *string
+	WebsiteRoot     *string
+}
+
+func New(cmd *kingpin.CmdClause) *Command {
+	return &Command{
+		DataDirectory:   cmd.Flag("data-directory", "Data directory").Required().String(),
+		OutputDirectory: cmd.Flag("output-directory", "Directory receiving sitemap.xml, the per-type sitemaps and robots.txt").Required().String(),
+		WebsiteRoot:     cmd.Flag("website-root", "Root of the urls of the sitemaps").Default("https://raid-codex.com").String(),
+	}
+}
+
+func (c *Command) Run() {
+	errFactory := common.InitFactory(*c.DataDirectory)
+	if errFactory != nil {
+		utils.Exit(1, errFactory)
+	}
+	sections, errSections := site.Sections()
+	if errSections != nil {
+		utils.Exit(1, errSections)
+	}
+	errMkdir := os.MkdirAll(*c.OutputDirectory, 0755)
+	if errMkdir != nil {
+		utils.Exit(1, errMkdir)
+	}
+	errSitemaps := site.Sitemaps(sections, *c.DataDirectory, *c.WebsiteRoot, *c.OutputDirectory)
+	if errSitemaps != nil {
+		utils.Exit(1, errSitemaps)
+	}
+}
@@ -1,28 +1,50 @@
 package website_cache_clear
 
 import (
+	"bufio"
 	"context"
 	"fmt"
 	"log"
 	"os"
+	"strings"
 
-	"github.com/cloudflare/cloudflare-go"
+	"github.com/raid-codex/tools/common"
+	"github.com/raid-codex/tools/purge"
 	"github.com/raid-codex/tools/utils"
 	"gopkg.in/alecthomas/kingpin.v2"
 )
 
 type Command struct {
+	DataDirectory *string
+	Champions     *[]string
+	Factions      *[]string
+	StatusEffects *[]string
+	Fusions       *[]string
+	Files         *[]string
+	FilesFrom     *string
+	Tags          *bool
+	Since         *string
+	WebsiteRoot   *string
+	DryRun        *bool
 }
 
 func New(cmd *kingpin.CmdClause) *Command {
-	command := &Command{}
+	command := &Command{
+		DataDirectory: cmd.Flag("data-directory", "Data directory, required to find the pages depending on the changed entities").String(),
+		Champions:     cmd.Flag("champion", "Slug of a changed champion").Strings(),
+		Factions:      cmd.Flag("faction", "Slug of a changed faction").Strings(),
+		StatusEffects: cmd.Flag("status-effect", "Slug of a changed status effect").Strings(),
+		Fusions:       cmd.Flag("fusion", "Slug of a changed fusion").Strings(),
+		Files:         cmd.Flag("file", "Changed data file, e.g. docs/champions/current/kael.json").Strings(),
+		FilesFrom:     cmd.Flag("files-from", "File listing the changed data files, one per line, e.g. /dev/stdin to read the output of git diff --name-only").String(),
+		Tags:          cmd.Flag("tags", "Purge the cache tags of the changed entities instead of the urls of their pages (Enterprise only)").Bool(),
+		Since:         cmd.Flag("since", "Git revision of the data directory, the entities changed since then are purged along with the pages renamed or deleted champions were on").String(),
+		WebsiteRoot:   cmd.Flag("website-root", "Root of the urls to purge").Default("https://raid-codex.com").String(),
+		DryRun:        cmd.Flag("dry-run", "Print what would be purged").Bool(),
+	}
 	return command
 }
 
-var (
-	zoneID string = os.Getenv("CF_ZONE_ID")
-)
-
 func (c *Command) Run() {
 	err := c.run()
 	if err != nil {
@@ -31,19 +53,129 @@ func (c *Command) Run() {
 }
 
 func (c *Command) run() error {
-	cf, err := cloudflare.New(os.Getenv("CF_API_KEY"), os.Getenv("CF_API_EMAIL"))
-	if err != nil {
-		return err
+	changes, errChanges := c.changes()
+	if errChanges != nil {
+		return errChanges
 	}
-	log.Println("purging Cloudflare cache")
-	resp, err := cf.PurgeCache(context.TODO(), zoneID, cloudflare.PurgeCacheRequest{
-		Everything: true,
-	})
-	if err != nil {
-		return err
-	} else if !resp.Success {
-		return fmt.Errorf("could not purge cache")
+	if !c.targeted() {
+		if *c.DryRun {
+			fmt.Println("would purge everything")
+			return nil
+		}
+		cf, errCloudflare := purge.CloudflareFromEnv()
+		if errCloudflare != nil {
+			return errCloudflare
+		}
+		log.Println("purging Cloudflare cache")
+		if errPurge := cf.Everything(context.TODO()); errPurge != nil {
+			return errPurge
+		}
+		log.Println("cache purged")
+		return nil
+	} else if len(changes) == 0 {
+		log.Println("no entity changed, nothing to purge")
+		return nil
+	}
+	if *c.Tags {
+		tags := purge.Tags(changes)
+		if *c.DryRun {
+			fmt.Println(strings.Join(tags, "\n"))
+			return nil
+		}
+		cf, errCloudflare := purge.CloudflareFromEnv()
+		if errCloudflare != nil {
+			return errCloudflare
+		}
+		log.Printf("purging %d tags\n", len(tags))
+		if errPurge := cf.Tags(context.TODO(), tags); errPurge != nil {
+			return errPurge
+		}
+		log.Println("cache purged")
+		return nil
+	}
+	if *c.DataDirectory == "" {
+		return fmt.Errorf("--data-directory is required to purge the pages of the changed entities")
+	}
+	errFactory := common.InitFactory(*c.DataDirectory)
+	if errFactory != nil {
+		return errFactory
+	}
+	pages, errPages := purge.Pages(changes)
+	if errPages != nil {
+		return errPages
+	}
+	urls := make([]string, len(pages))
+	for idx, page := range pages {
+		urls[idx] = fmt.Sprintf("%s%s", strings.TrimSuffix(*c.WebsiteRoot, "/"), page)
+	}
+	if *c.DryRun {
+		fmt.Println(strings.Join(urls, "\n"))
+		return nil
+	}
+	cf, errCloudflare := purge.CloudflareFromEnv()
+	if errCloudflare != nil {
+		return errCloudflare
+	}
+	log.Printf("purging %d urls\n", len(urls))
+	if errPurge := cf.Files(context.TODO(), urls); errPurge != nil {
+		return errPurge
 	}
 	log.Println("cache purged")
 	return nil
 }
+
+// targeted tells whether changes were given, the whole zone is purged otherwise
+func (c *Command) targeted() bool {
+	return len(*c.Champions) > 0 || len(*c.Factions) > 0 || len(*c.StatusEffects) > 0 ||
+		len(*c.Fusions) > 0 || len(*c.Files) > 0 || *c.FilesFrom != "" || *c.Since != ""
+}
+
+func (c *Command) changes() ([]purge.Change, error) {
+	changes := make([]purge.Change, 0)
+	for kind, slugs := range map[string][]string{
+		purge.Kind_Champion:     *c.Champions,
+		purge.Kind_Faction:      *c.Factions,
+		purge.Kind_StatusEffect: *c.StatusEffects,
+		purge.Kind_Fusion:       *c.Fusions,
+	} {
+		for _, slug := range slugs {
+			changes = append(changes, purge.Change{Kind: kind, Slug: slug})
+		}
+	}
+	files := append([]string{}, *c.Files...)
+	if *c.FilesFrom != "" {
+		file, errOpen := os.Open(*c.FilesFrom)
+		if errOpen != nil {
+			return nil, errOpen
+		}
+		defer file.Close()
+		scanner := bufio.NewScanner(file)
+		for scanner.Scan() {
+			if line := strings.TrimSpace(scanner.Text()); line != "" {
+				files = append(files, line)
+			}
+		}
+		if errScan := scanner.Err(); errScan != nil {
+			return nil, errScan
+		}
+	}
+	if *c.Since != "" {
+		if *c.DataDirectory == "" {
+			return nil, fmt.Errorf("--data-directory is required to list the changes since %s", *c.Since)
+		}
+		since, errSince := purge.ChangesSince(*c.DataDirectory, *c.Since)
+		if errSince != nil {
+			return nil, errSince
+		}
+		changes = append(changes, since...)
+	}
+	for _, file := range files {
+		change, ok := purge.ChangeOfFile(file)
+		if !ok {
+			log.Printf("ignoring %s, it is not the file of an entity\n", file)
+			continue
+		}
+		changes = append(changes, change)
+	}
+	return changes, nil
+}
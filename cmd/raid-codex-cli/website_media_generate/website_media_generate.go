@@ -0,0 +1,89 @@
+package website_media_generate
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/raid-codex/tools/common"
+	"github.com/raid-codex/tools/media"
+	"github.com/raid-codex/tools/utils"
+	"gopkg.in/alecthomas/kingpin.v2"
+)
+
+type Command struct {
+	DataDirectory *string
+	ImageFolder   *string
+	Name          *string
+	WebP          *bool
+	CWebPBinary   *string
+	Quality       *int
+	Force         *bool
+}
+
+func New(cmd *kingpin.CmdClause) *Command {
+	return &Command{
+		DataDirectory: cmd.Flag("data-directory", "Data directory").Required().String(),
+		ImageFolder:   cmd.Flag("image-folder", "Folder of the images, laid out like wp-content/uploads, the portraits are read from champions/").Required().String(),
+		Name:          cmd.Flag("name", "Name of the champion, every champion when empty").String(),
+		WebP:          cmd.Flag("webp", "Write the WebP variants, with cwebp").Default("true").Bool(),
+		CWebPBinary:   cmd.Flag("cwebp-binary", "Path of cwebp").Default("cwebp").String(),
+		Quality:       cmd.Flag("quality", "Quality of the WebP variants").Default("80").Int(),
+		Force:         cmd.Flag("force", "Write the variants even when they are newer than the portrait").Bool(),
+	}
+}
+
+func (c *Command) Run() {
+	errFactory := common.InitFactory(*c.DataDirectory)
+	if errFactory != nil {
+		utils.Exit(1, errFactory)
+	}
+	champions, errChampions := common.GetChampions()
+	if errChampions != nil {
+		utils.Exit(1, errChampions)
+	}
+	if *c.Name != "" {
+		champion, errChampion := common.FindChampion(*c.Name)
+		if errChampion != nil {
+			utils.Exit(1, errChampion)
+		}
+		champions = common.ChampionList{champion}
+	}
+	generator := &media.Generator{
+		Folder: *c.ImageFolder,
+		Force:  *c.Force,
+	}
+	if *c.WebP {
+		generator.WebP = &media.CWebP{Binary: *c.CWebPBinary, Quality: *c.Quality}
+	}
+	written, upToDate := 0, 0
+	missing := make([]string, 0)
+	failed := make([]string, 0)
+	for _, champion := range champions {
+		generated, errGenerate := generator.Champion(champion)
+		if errors.IsNotFound(errGenerate) {
+			missing = append(missing, champion.Slug)
+			continue
+		} else if errGenerate != nil {
+			log.Printf("%s: %v\n", champion.Slug, errGenerate)
+			failed = append(failed, champion.Slug)
+			continue
+		}
+		for _, path := range generated.Written {
+			fmt.Printf("%s\n", path)
+		}
+		for _, path := range generated.Skipped {
+			fmt.Printf("%s: skipped, the portrait is not wider\n", path)
+		}
+		written += len(generated.Written)
+		upToDate += len(generated.UpToDate)
+	}
+	fmt.Printf("%d images written, %d up to date\n", written, upToDate)
+	if len(missing) > 0 {
+		fmt.Printf("%d champions without a portrait: %s\n", len(missing), strings.Join(missing, ", "))
+	}
+	if len(failed) > 0 {
+		utils.Exit(1, fmt.Errorf("%d champions failed: %s", len(failed), strings.Join(failed, ", ")))
+	}
+}
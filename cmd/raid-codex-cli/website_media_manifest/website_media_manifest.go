@@ -0,0 +1,55 @@
+package website_media_manifest
+
+import (
+	"fmt"
+
+	"github.com/raid-codex/tools/common"
+	"github.com/raid-codex/tools/media"
+	"github.com/raid-codex/tools/utils"
+	"gopkg.in/alecthomas/kingpin.v2"
+)
+
+type Command struct {
+	UploadsFolder *string
+	Output        *string
+	DataDirectory *string
+}
+
+func New(cmd *kingpin.CmdClause) *Command {
+	return &Command{
+		UploadsFolder: cmd.Flag("uploads-folder", "Local mirror of wp-content/uploads").Required().String(),
+		Output:        cmd.Flag("output", "Manifest file, used by the templates when passed as --image-manifest").Default("image-manifest.json").String(),
+		DataDirectory: cmd.Flag("data-directory", "Data directory, when set the images of the data missing from the manifest are reported").String(),
+	}
+}
+
+func (c *Command) Run() {
+	manifest, errManifest := media.BuildManifest(*c.UploadsFolder)
+	if errManifest != nil {
+		utils.Exit(1, errManifest)
+	}
+	errSave := manifest.Save(*c.Output)
+	if errSave != nil {
+		utils.Exit(1, errSave)
+	}
+	fmt.Printf("%d images in %s\n", len(manifest.Paths()), *c.Output)
+	if *c.DataDirectory == "" {
+		return
+	}
+	errFactory := common.InitFactory(*c.DataDirectory)
+	if errFactory != nil {
+		utils.Exit(1, errFactory)
+	}
+	refs, errRefs := media.References()
+	if errRefs != nil {
+		utils.Exit(1, errRefs)
+	}
+	missing := 0
+	for _, ref := range refs {
+		if !ref.Derived && !manifest.Has(ref.Path) {
+			fmt.Printf("missing %s (%s)\n", ref.Path, ref.Owner)
+			missing++
+		}
+	}
+	fmt.Printf("%d images of the data missing\n", missing)
+}
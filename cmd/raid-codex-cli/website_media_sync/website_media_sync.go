@@ -0,0 +1,57 @@
+package website_media_sync
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/raid-codex/tools/common"
+	"github.com/raid-codex/tools/media"
+	"github.com/raid-codex/tools/publish"
+	"github.com/raid-codex/tools/utils"
+	"gopkg.in/alecthomas/kingpin.v2"
+)
+
+type Command struct {
+	DataDirectory *string
+	ImageFolder   *string
+	DryRun        *bool
+	Workers       *int
+}
+
+func New(cmd *kingpin.CmdClause) *Command {
+	return &Command{
+		DataDirectory: cmd.Flag("data-directory", "Data directory").Required().String(),
+		ImageFolder:   cmd.Flag("image-folder", "Folder of the images, laid out like wp-content/uploads (champions/, champion-thumbnails/, hashed-img/, factions/, status-effects/)").Required().String(),
+		DryRun:        cmd.Flag("dry-run", "Report the images to upload without uploading them").Bool(),
+		Workers:       cmd.Flag("workers", "Number of images checked and uploaded in parallel").Default("4").Int(),
+	}
+}
+
+func (c *Command) Run() {
+	publisher, errPublisher := publish.FromEnv()
+	if errPublisher != nil {
+		utils.Exit(1, errPublisher)
+	}
+	errFactory := common.InitFactory(*c.DataDirectory)
+	if errFactory != nil {
+		utils.Exit(1, errFactory)
+	}
+	refs, errRefs := media.References()
+	if errRefs != nil {
+		utils.Exit(1, errRefs)
+	}
+	sync := &media.Sync{
+		Folder:    *c.ImageFolder,
+		Publisher: publish.WithRetry(publisher, 3, time.Second),
+		DryRun:    *c.DryRun,
+		Workers:   *c.Workers,
+	}
+	report, errSync := sync.Run(refs)
+	if errSync != nil {
+		utils.Exit(1, errSync)
+	}
+	fmt.Println(report)
+	if len(report.Missing) > 0 || len(report.Failed) > 0 {
+		utils.Exit(1, fmt.Errorf("%d images missing, %d failed", len(report.Missing), len(report.Failed)))
+	}
+}
@@ -0,0 +1,201 @@
+package common
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// aliases.json lists how third parties write names that differ from ours:
+//   - champions: our champion name -> names used by sheets and sites
+//   - factions: our faction slug -> slugs we used to have
+//   - source_slugs: source -> our champion slug -> slug used by that source in its URLs
+//
+//go:embed aliases.json
+var aliasesData []byte
+
+type AliasRegistry struct {
+	Champions   map[string][]string          `json:"champions"`
+	Factions    map[string][]string          `json:"factions"`
+	SourceSlugs map[string]map[string]string `json:"source_slugs"`
+
+	championByAlias map[string]string
+	factionByAlias  map[string]string
+}
+
+var aliases = mustLoadAliases()
+
+func mustLoadAliases() *AliasRegistry {
+	registry := &AliasRegistry{}
+	if err := json.Unmarshal(aliasesData, registry); err != nil {
+		panic(fmt.Errorf("invalid aliases.json: %s", err))
+	}
+	registry.championByAlias = map[string]string{}
+	for name, list := range registry.Champions {
+		for _, alias := range list {
+			registry.championByAlias[alias] = name
+		}
+	}
+	registry.factionByAlias = map[string]string{}
+	for slug, list := range registry.Factions {
+		for _, alias := range list {
+			registry.factionByAlias[alias] = slug
+		}
+	}
+	return registry
+}
+
+// ResolveChampionName returns the name we use for a champion spelled name by a third party
+func ResolveChampionName(name string) string {
+	name = strings.TrimSpace(name)
+	if v, ok := aliases.championByAlias[name]; ok {
+		return v
+	}
+	return name
+}
+
+// ResolveFactionSlug returns the current slug of a faction from one we used to have
+func ResolveFactionSlug(slug string) string {
+	if v, ok := aliases.factionByAlias[slug]; ok {
+		return v
+	}
+	return slug
+}
+
+// SourceSlug returns the slug source uses in its URLs for the champion with our slug
+func SourceSlug(source, slug string) string {
+	if v, ok := aliases.SourceSlugs[source][slug]; ok {
+		return v
+	}
+	return slug
+}
+
+var nameNormalizer = strings.NewReplacer(
+	"’", "", "‘", "", "`", "", "'", "", "´", "",
+	"-", "", " ", "", "_", "", ".", "",
+)
+
+// NormalizeName folds what third parties get wrong when writing a name: case, quotes,
+// apostrophes, hyphens and spaces
+func NormalizeName(name string) string {
+	return nameNormalizer.Replace(strings.ToLower(strings.TrimSpace(name)))
+}
+
+// MatchName returns the candidate name stands for, either after resolving aliases or
+// because they only differ by what NormalizeName folds
+func MatchName(name string, candidates []string) (string, bool) {
+	name = ResolveChampionName(name)
+	normalized := NormalizeName(name)
+	for _, candidate := range candidates {
+		if candidate == name {
+			return candidate, true
+		}
+	}
+	for _, candidate := range candidates {
+		if NormalizeName(candidate) == normalized {
+			return candidate, true
+		}
+	}
+	return "", false
+}
+
+// SuggestNames returns up to max candidates close enough to name to be a typo, the closest first
+func SuggestNames(name string, candidates []string, max int) []string {
+	normalized := NormalizeName(ResolveChampionName(name))
+	threshold := len([]rune(normalized))/4 + 1
+	type suggestion struct {
+		name     string
+		distance int
+	}
+	suggestions := make([]suggestion, 0)
+	for _, candidate := range candidates {
+		distance := levenshtein(normalized, NormalizeName(candidate))
+		if distance <= threshold {
+			suggestions = append(suggestions, suggestion{name: candidate, distance: distance})
+		}
+	}
+	sort.SliceStable(suggestions, func(i, j int) bool {
+		if suggestions[i].distance == suggestions[j].distance {
+			return suggestions[i].name < suggestions[j].name
+		}
+		return suggestions[i].distance < suggestions[j].distance
+	})
+	names := make([]string, 0, max)
+	for idx := 0; idx < len(suggestions) && idx < max; idx++ {
+		names = append(names, suggestions[idx].name)
+	}
+	return names
+}
+
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	previous := make([]int, len(rb)+1)
+	current := make([]int, len(rb)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		current[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			current[j] = minInt(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(rb)]
+}
+
+func minInt(values ...int) int {
+	m := values[0]
+	for _, v := range values[1:] {
+		if v < m {
+			m = v
+		}
+	}
+	return m
+}
+
+// ChampionNotFoundError is returned when a name matches no champion, with the closest names if any
+type ChampionNotFoundError struct {
+	Name        string
+	Suggestions []string
+}
+
+func (e *ChampionNotFoundError) Error() string {
+	if len(e.Suggestions) == 0 {
+		return fmt.Sprintf("champion %s not found", e.Name)
+	}
+	return fmt.Sprintf("champion %s not found, did you mean %s?", e.Name, strings.Join(e.Suggestions, " or "))
+}
+
+func NewChampionNotFoundError(name string, candidates []string) *ChampionNotFoundError {
+	return &ChampionNotFoundError{Name: name, Suggestions: SuggestNames(name, candidates, 3)}
+}
+
+// FindChampion returns the champion of the factory named name, tolerating aliases and
+// spelling variants; a ChampionNotFoundError is returned otherwise
+func FindChampion(name string) (*Champion, error) {
+	champions, errChampions := GetChampions()
+	if errChampions != nil {
+		return nil, errChampions
+	}
+	names := make([]string, len(champions))
+	for idx, champion := range champions {
+		names[idx] = champion.Name
+	}
+	match, ok := MatchName(name, names)
+	if !ok {
+		return nil, NewChampionNotFoundError(name, names)
+	}
+	for _, champion := range champions {
+		if champion.Name == match {
+			return champion, nil
+		}
+	}
+	return nil, NewChampionNotFoundError(name, names)
+}
@@ -44,6 +44,7 @@ type Champion struct {
 	FusionData         []*ChampionFusionData     `json:"fusion_data"`
 	EffectSlugs        []string                  `json:"effect_slugs"`
 	Videos             []*Video                  `json:"videos"`
+	Provenance         []*FieldProvenance        `json:"provenance"`
 }
 
 type ChampionFusionData struct {
@@ -84,10 +85,7 @@ func (c *Champion) Sanitize() error {
 	c.Slug = c.LinkName()
 
 	// faction
-	// -- for compat
-	if c.FactionSlug == "skinwalker" {
-		c.FactionSlug = "skinwalkers"
-	}
+	c.FactionSlug = ResolveFactionSlug(c.FactionSlug)
 	factions, errFactions := GetFactions(FilterFactionSlug(c.FactionSlug))
 	if errFactions != nil {
 		return errFactions
@@ -127,7 +125,9 @@ func (c *Champion) Sanitize() error {
 	}
 
 	if c.SEO == nil {
-		c.DefaultSEO()
+		if errSEO := c.DefaultSEO(); errSEO != nil {
+			return errSEO
+		}
 	}
 
 	c.defaultRating()
@@ -238,6 +238,10 @@ func (c *Champion) Sanitize() error {
 		return err
 	}
 
+	if err := c.sanitizeProvenance(); err != nil {
+		return err
+	}
+
 	// don't store champion slugs on factions / effects
 	c.Faction.ChampionSlugs = []string{}
 	for _, skill := range c.Skills {
@@ -342,7 +346,7 @@ func (c *Champion) defaultRating() {
 	}
 }
 
-func (c *Champion) DefaultSEO() {
+func (c *Champion) DefaultSEO() error {
 	c.SEO = &seo.SEO{
 		Title:       "%%title%% %%page%% %%sep%% %%parent_title%% %%sep%% %%sitename%%",
 		Description: fmt.Sprintf("%s. Find out more on this Raid Shadow Legends codex.", c.DefaultDescription),
@@ -351,21 +355,12 @@ func (c *Champion) DefaultSEO() {
 		},
 		StructuredData: []json.RawMessage{},
 	}
-	championDoc := map[string]interface{}{
-		"@context":    "http://schema.org/",
-		"@type":       "Person",
-		"name":        c.Name,
-		"url":         fmt.Sprintf("https://raid-codex.com%s", c.WebsiteLink),
-		"image":       fmt.Sprintf("https://raid-codex.com/img/hashed-img/%s.png", c.Thumbnail),
-		"description": fmt.Sprintf("Member of the faction %s, %s is a champion of %s rarity and of %s type", c.Faction.Name, c.Name, c.Rarity, c.Type),
-		"affiliation": map[string]interface{}{
-			"@type":    "Organization",
-			"@context": "http://schema.org",
-			"name":     c.Faction.Name,
-		},
+	structuredData, errStructuredData := c.StructuredData(DefaultWebsiteRoot)
+	if errStructuredData != nil {
+		return errStructuredData
 	}
-	rawMessage, _ := json.Marshal(championDoc)
-	c.SEO.StructuredData = append(c.SEO.StructuredData, json.RawMessage(rawMessage))
+	c.SEO.StructuredData = structuredData
+	return nil
 }
 
 func (c Champion) Filename() string {
@@ -414,6 +409,8 @@ func (_ Champion) GetParentPageID() int { return 29 }
 
 func (c Champion) GetPageExcerpt() string { return c.DefaultDescription }
 
+func (c Champion) GetWebsiteLink() string { return fmt.Sprintf("/champions/%s/", c.Slug) }
+
 func (c *Champion) GetPageContent_Templates(tmpl *template.Template, output io.Writer, extraData map[string]interface{}) error {
 	effects := map[string]*StatusEffect{}
 	for _, skill := range c.Skills {
@@ -648,6 +645,12 @@ func (c *Champion) setSkillFromRaw(raw string) error {
 func (c *Champion) GetPageExtraData(dataDirectory string) (map[string]interface{}, error) {
 	data := map[string]interface{}{}
 
+	structuredData, errStructuredData := c.StructuredData(DefaultWebsiteRoot)
+	if errStructuredData != nil {
+		return nil, errStructuredData
+	}
+	data["StructuredData"] = structuredData
+
 	if dataDirectory == "" {
 		return data, nil
 	}
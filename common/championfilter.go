@@ -92,3 +92,29 @@ func FilterChampionSlug(slug string) ChampionFilter {
 func FilterChampionName(name string) ChampionFilter {
 	return func(champion *Champion) bool { return champion.Name == name }
 }
+
+func FilterChampionAnd(filters ...ChampionFilter) ChampionFilter {
+	return func(champion *Champion) bool {
+		for _, filter := range filters {
+			if !filter(champion) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+func FilterChampionOr(filters ...ChampionFilter) ChampionFilter {
+	return func(champion *Champion) bool {
+		for _, filter := range filters {
+			if filter(champion) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+func FilterChampionNot(filter ChampionFilter) ChampionFilter {
+	return func(champion *Champion) bool { return !filter(champion) }
+}
@@ -0,0 +1,374 @@
+package common
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"unicode"
+)
+
+// ParseChampionFilter compiles a filter expression into a ChampionFilter.
+//
+// An expression compares fields to values and combines comparisons with and, or, not and
+// parentheses, e.g.
+//
+//	rarity = epic and element = void and type = support and effect.A1 = poison
+//	and rating.clan_boss_with_giant_slayer >= A
+//
+// Fields are faction (slug or name), rarity, element, type, rating.<location> (json name of
+// Rating), effect (on any skill), effect.<skill number>, target.<effect> (who the effect targets),
+// aura.stat, aura.location, fusion (any fusion the champion belongs to) and fusion.<fusion type>.
+// Operators are =, != and in (a, b, ...), rarity and rating also accept <, <=, > and >=.
+// Values containing spaces are double-quoted, comparisons ignore case.
+func ParseChampionFilter(expression string) (ChampionFilter, error) {
+	tokens, errLex := lexChampionFilter(expression)
+	if errLex != nil {
+		return nil, errLex
+	}
+	p := &championFilterParser{tokens: tokens}
+	filter, errParse := p.parseOr()
+	if errParse != nil {
+		return nil, errParse
+	}
+	if tok := p.peek(); tok.kind != filterToken_EOF {
+		return nil, fmt.Errorf("unexpected %q at position %d", tok.value, tok.pos)
+	}
+	return filter, nil
+}
+
+const (
+	filterToken_EOF = iota
+	filterToken_Word
+	filterToken_String
+	filterToken_Operator
+	filterToken_LeftParen
+	filterToken_RightParen
+	filterToken_Comma
+)
+
+type filterToken struct {
+	kind  int
+	value string
+	pos   int
+}
+
+func lexChampionFilter(expression string) ([]filterToken, error) {
+	tokens := make([]filterToken, 0)
+	runes := []rune(expression)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(':
+			tokens = append(tokens, filterToken{kind: filterToken_LeftParen, value: "(", pos: i})
+			i++
+		case r == ')':
+			tokens = append(tokens, filterToken{kind: filterToken_RightParen, value: ")", pos: i})
+			i++
+		case r == ',':
+			tokens = append(tokens, filterToken{kind: filterToken_Comma, value: ",", pos: i})
+			i++
+		case r == '=' || r == '!' || r == '<' || r == '>':
+			op := string(r)
+			if i+1 < len(runes) && runes[i+1] == '=' {
+				op += "="
+			}
+			if op == "!" {
+				return nil, fmt.Errorf("unexpected ! at position %d, did you mean !=", i)
+			}
+			tokens = append(tokens, filterToken{kind: filterToken_Operator, value: op, pos: i})
+			i += len(op)
+		case r == '"':
+			end := i + 1
+			for end < len(runes) && runes[end] != '"' {
+				end++
+			}
+			if end == len(runes) {
+				return nil, fmt.Errorf("unterminated string at position %d", i)
+			}
+			tokens = append(tokens, filterToken{kind: filterToken_String, value: string(runes[i+1 : end]), pos: i})
+			i = end + 1
+		default:
+			end := i
+			for end < len(runes) && !unicode.IsSpace(runes[end]) && !strings.ContainsRune("()=!<>,\"", runes[end]) {
+				end++
+			}
+			tokens = append(tokens, filterToken{kind: filterToken_Word, value: string(runes[i:end]), pos: i})
+			i = end
+		}
+	}
+	return append(tokens, filterToken{kind: filterToken_EOF, pos: len(runes)}), nil
+}
+
+type championFilterParser struct {
+	tokens []filterToken
+	pos    int
+}
+
+func (p *championFilterParser) peek() filterToken {
+	return p.tokens[p.pos]
+}
+
+func (p *championFilterParser) next() filterToken {
+	tok := p.tokens[p.pos]
+	if tok.kind != filterToken_EOF {
+		p.pos++
+	}
+	return tok
+}
+
+func (p *championFilterParser) keyword(word string) bool {
+	tok := p.peek()
+	if tok.kind == filterToken_Word && strings.EqualFold(tok.value, word) {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *championFilterParser) parseOr() (ChampionFilter, error) {
+	filters := make([]ChampionFilter, 0)
+	for {
+		filter, errAnd := p.parseAnd()
+		if errAnd != nil {
+			return nil, errAnd
+		}
+		filters = append(filters, filter)
+		if !p.keyword("or") {
+			break
+		}
+	}
+	if len(filters) == 1 {
+		return filters[0], nil
+	}
+	return FilterChampionOr(filters...), nil
+}
+
+func (p *championFilterParser) parseAnd() (ChampionFilter, error) {
+	filters := make([]ChampionFilter, 0)
+	for {
+		filter, errUnary := p.parseUnary()
+		if errUnary != nil {
+			return nil, errUnary
+		}
+		filters = append(filters, filter)
+		if !p.keyword("and") {
+			break
+		}
+	}
+	if len(filters) == 1 {
+		return filters[0], nil
+	}
+	return FilterChampionAnd(filters...), nil
+}
+
+func (p *championFilterParser) parseUnary() (ChampionFilter, error) {
+	if p.keyword("not") {
+		filter, errUnary := p.parseUnary()
+		if errUnary != nil {
+			return nil, errUnary
+		}
+		return FilterChampionNot(filter), nil
+	}
+	if p.peek().kind == filterToken_LeftParen {
+		p.next()
+		filter, errOr := p.parseOr()
+		if errOr != nil {
+			return nil, errOr
+		}
+		if tok := p.next(); tok.kind != filterToken_RightParen {
+			return nil, fmt.Errorf("expected ) at position %d", tok.pos)
+		}
+		return filter, nil
+	}
+	return p.parseComparison()
+}
+
+func (p *championFilterParser) parseComparison() (ChampionFilter, error) {
+	field := p.next()
+	if field.kind != filterToken_Word {
+		return nil, fmt.Errorf("expected a field at position %d", field.pos)
+	}
+	if p.keyword("in") {
+		values, errValues := p.parseList()
+		if errValues != nil {
+			return nil, errValues
+		}
+		filters := make([]ChampionFilter, 0, len(values))
+		for _, value := range values {
+			filter, errField := compileChampionComparison(field, "=", value)
+			if errField != nil {
+				return nil, errField
+			}
+			filters = append(filters, filter)
+		}
+		return FilterChampionOr(filters...), nil
+	}
+	op := p.next()
+	if op.kind != filterToken_Operator {
+		return nil, fmt.Errorf("expected an operator after %s at position %d", field.value, op.pos)
+	}
+	value := p.next()
+	if value.kind != filterToken_Word && value.kind != filterToken_String {
+		return nil, fmt.Errorf("expected a value after %s %s at position %d", field.value, op.value, value.pos)
+	}
+	return compileChampionComparison(field, op.value, value.value)
+}
+
+func (p *championFilterParser) parseList() ([]string, error) {
+	if tok := p.next(); tok.kind != filterToken_LeftParen {
+		return nil, fmt.Errorf("expected ( at position %d", tok.pos)
+	}
+	values := make([]string, 0)
+	for {
+		value := p.next()
+		if value.kind != filterToken_Word && value.kind != filterToken_String {
+			return nil, fmt.Errorf("expected a value at position %d", value.pos)
+		}
+		values = append(values, value.value)
+		sep := p.next()
+		if sep.kind == filterToken_RightParen {
+			return values, nil
+		} else if sep.kind != filterToken_Comma {
+			return nil, fmt.Errorf("expected , or ) at position %d", sep.pos)
+		}
+	}
+}
+
+func compileChampionComparison(field filterToken, op, value string) (ChampionFilter, error) {
+	name, argument := field.value, ""
+	if idx := strings.Index(name, "."); idx >= 0 {
+		name, argument = name[:idx], name[idx+1:]
+	}
+	name = strings.ToLower(name)
+	var filter ChampionFilter
+	switch {
+	case name == "rarity" && argument == "":
+		return orderedChampionFilter(field, op, value, Rarities, func(champion *Champion) string { return champion.Rarity })
+	case name == "rating" && argument != "":
+		getter, errLocation := ratingGetter(argument)
+		if errLocation != nil {
+			return nil, fmt.Errorf("%s at position %d", errLocation, field.pos)
+		}
+		return orderedChampionFilter(field, op, value, RatingGrades, getter)
+	case name == "faction" && argument == "":
+		filter = func(champion *Champion) bool {
+			return strings.EqualFold(champion.FactionSlug, value) || strings.EqualFold(champion.Faction.Name, value) ||
+				champion.FactionSlug == GetLinkNameFromSanitizedName(value)
+		}
+	case name == "element" && argument == "":
+		filter = func(champion *Champion) bool { return strings.EqualFold(champion.Element, value) }
+	case name == "type" && argument == "":
+		filter = func(champion *Champion) bool { return strings.EqualFold(champion.Type, value) }
+	case name == "effect" && argument == "":
+		filter = FilterChampionStatusEffect(strings.ToLower(value))
+	case name == "effect":
+		filter = FilterChampionStatusEffectOnSkill(strings.ToUpper(argument), strings.ToLower(value))
+	case name == "target" && argument != "":
+		filter = FilterChampionStatusEffectWithTargets(strings.ToLower(argument), strings.ToLower(value))
+	case name == "aura" && argument == "stat":
+		filter = func(champion *Champion) bool {
+			for _, aura := range champion.Auras {
+				if containsFold(aura.Stats, value) {
+					return true
+				}
+			}
+			return false
+		}
+	case name == "aura" && argument == "location":
+		filter = func(champion *Champion) bool {
+			for _, aura := range champion.Auras {
+				if containsFold(aura.Locations, value) {
+					return true
+				}
+			}
+			return false
+		}
+	case name == "fusion":
+		filter = func(champion *Champion) bool {
+			for _, data := range champion.FusionData {
+				if strings.EqualFold(data.FusionSlug, value) && (argument == "" || strings.EqualFold(data.FusionType, argument)) {
+					return true
+				}
+			}
+			return false
+		}
+	default:
+		return nil, fmt.Errorf("unknown field %s at position %d", field.value, field.pos)
+	}
+	switch op {
+	case "=":
+		return filter, nil
+	case "!=":
+		return FilterChampionNot(filter), nil
+	}
+	return nil, fmt.Errorf("%s cannot be compared with %s at position %d", field.value, op, field.pos)
+}
+
+// orderedChampionFilter compares the value returned by get with value, following the order of values.
+// When values starts with "", e.g. RatingGrades, the champions without a value only match comparisons
+// with "" itself, so that rating < B does not match unrated champions.
+func orderedChampionFilter(field filterToken, op, value string, values []string, get func(*Champion) string) (ChampionFilter, error) {
+	rank := func(v string) int {
+		for idx, allowed := range values {
+			if strings.EqualFold(allowed, v) {
+				return idx
+			}
+		}
+		return -1
+	}
+	expected := rank(value)
+	if expected < 0 {
+		allowed := make([]string, 0, len(values))
+		for _, v := range values {
+			if v != "" {
+				allowed = append(allowed, v)
+			}
+		}
+		return nil, fmt.Errorf("unknown value %s for %s at position %d, expected one of %s", value, field.value, field.pos, strings.Join(allowed, ", "))
+	}
+	compare := map[string]func(int) bool{
+		"=":  func(r int) bool { return r == expected },
+		"!=": func(r int) bool { return r != expected },
+		"<":  func(r int) bool { return r < expected },
+		"<=": func(r int) bool { return r <= expected },
+		">":  func(r int) bool { return r > expected },
+		">=": func(r int) bool { return r >= expected },
+	}[op]
+	excludeUnset := values[0] == "" && expected != 0
+	return func(champion *Champion) bool {
+		actual := rank(get(champion))
+		if excludeUnset && actual == 0 {
+			return false
+		}
+		return actual >= 0 && compare(actual)
+	}, nil
+}
+
+// ratingGetter returns the grade of a champion in a location, named after the json names of Rating
+func ratingGetter(location string) (func(*Champion) string, error) {
+	ratingType := reflect.TypeOf(Rating{})
+	for i := 0; i < ratingType.NumField(); i++ {
+		if strings.Split(ratingType.Field(i).Tag.Get("json"), ",")[0] != strings.ToLower(location) {
+			continue
+		}
+		fieldIdx := i
+		return func(champion *Champion) string {
+			if champion.Rating == nil {
+				return ""
+			}
+			return reflect.ValueOf(champion.Rating).Elem().Field(fieldIdx).String()
+		}, nil
+	}
+	return nil, fmt.Errorf("unknown rating location %s", location)
+}
+
+func containsFold(list []string, value string) bool {
+	for _, item := range list {
+		if strings.EqualFold(item, value) {
+			return true
+		}
+	}
+	return false
+}
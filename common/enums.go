@@ -0,0 +1,14 @@
+package common
+
+var (
+	// Rarities lists the rarities of champions, from the lowest
+	Rarities = []string{"Common", "Uncommon", "Rare", "Epic", "Legendary"}
+	// Elements lists the affinities of champions, some champions have none yet
+	Elements = []string{"Force", "Magic", "Spirit", "Void"}
+	// ChampionTypes lists the roles of champions
+	ChampionTypes = []string{"Attack", "Defense", "HP", "Support"}
+	// RatingGrades lists the grades of a rating from the lowest, "" means not rated
+	RatingGrades = []string{"", "D", "C", "B", "A", "S", "SS"}
+	// SynergyContextKeys lists the known synergy contexts
+	SynergyContextKeys = []SynergyContextKey{SynergyContextKey_PoisonCounterattack}
+)
@@ -1,7 +1,6 @@
 package common
 
 import (
-	"encoding/json"
 	"fmt"
 	"html/template"
 	"io"
@@ -56,13 +55,15 @@ func (f *Faction) Sanitize() error {
 		)
 	}
 	if f.SEO == nil {
-		f.DefaultSEO()
+		if errSEO := f.DefaultSEO(); errSEO != nil {
+			return errSEO
+		}
 	}
 
 	return nil
 }
 
-func (f *Faction) DefaultSEO() {
+func (f *Faction) DefaultSEO() error {
 	f.SEO = &seo.SEO{
 		Title:       "%%title%% %%page%% %%sep%% %%parent_title%% %%sep%% %%sitename%%",
 		Description: fmt.Sprintf("%s. Find out more on this Raid Shadow Legends codex.", f.DefaultDescription),
@@ -70,16 +71,12 @@ func (f *Faction) DefaultSEO() {
 			"raid", "shadow", "legends", "factions", f.Name, f.Slug,
 		},
 	}
-	factionDoc := map[string]interface{}{
-		"@context":    "http://schema.org/",
-		"@type":       "Organization",
-		"name":        f.Name,
-		"url":         fmt.Sprintf("https://raid-codex.com%s", f.WebsiteLink),
-		"image":       fmt.Sprintf("https://raid-codex.com/wp-content/uploads/factions/%s.jpg", f.ImageSlug),
-		"description": f.RawDescription,
+	structuredData, errStructuredData := f.StructuredData(DefaultWebsiteRoot)
+	if errStructuredData != nil {
+		return errStructuredData
 	}
-	rawMessage, _ := json.Marshal(factionDoc)
-	f.SEO.StructuredData = append(f.SEO.StructuredData, json.RawMessage(rawMessage))
+	f.SEO.StructuredData = structuredData
+	return nil
 }
 
 func (f Faction) Filename() string {
@@ -114,8 +111,14 @@ func (f *Faction) GetPageContent_Templates(tmpl *template.Template, output io.Wr
 
 func (f Faction) GetPageExcerpt() string { return f.DefaultDescription }
 
+func (f Faction) GetWebsiteLink() string { return fmt.Sprintf("/factions/%s/", f.Slug) }
+
 func (f *Faction) GetPageExtraData(dataDirectory string) (map[string]interface{}, error) {
-	return map[string]interface{}{}, nil
+	structuredData, errStructuredData := f.StructuredData(DefaultWebsiteRoot)
+	if errStructuredData != nil {
+		return nil, errStructuredData
+	}
+	return map[string]interface{}{"StructuredData": structuredData}, nil
 }
 
 type FactionList []*Faction
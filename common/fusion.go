@@ -108,6 +108,8 @@ func (_ Fusion) GetParentPageID() int { return 11318 }
 func (f Fusion) GetPageExcerpt() string { return f.Name }
 func (f Fusion) LinkName() string       { return f.Slug }
 
+func (f Fusion) GetWebsiteLink() string { return fmt.Sprintf("/fusions/%s/", f.Slug) }
+
 func (f Fusion) GetPageContent(r io.Reader, output io.Writer, extraData map[string]interface{}) error {
 	return fmt.Errorf("not implemented")
 }
@@ -155,5 +157,10 @@ func (f *Fusion) GetPageExtraData(dataDirectory string) (map[string]interface{},
 		m[champion.Slug] = champion
 	}
 	data["Champions"] = m
+	structuredData, errStructuredData := f.StructuredData(DefaultWebsiteRoot)
+	if errStructuredData != nil {
+		return nil, errStructuredData
+	}
+	data["StructuredData"] = structuredData
 	return data, nil
 }
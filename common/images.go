@@ -0,0 +1,28 @@
+package common
+
+import "fmt"
+
+// UploadsPath is the path the images of the website are served under, image paths are relative to it
+const UploadsPath = "/wp-content/uploads/"
+
+// ChampionImagePath returns the path of the portrait of a champion, named after the image slug
+// Champion.Sanitize sets
+func ChampionImagePath(slug string) string {
+	return fmt.Sprintf("champions/image-champion-%s.jpg", slug)
+}
+
+// FactionImagePath returns the path of the image of a faction, imageSlug is set by Faction.Sanitize
+func FactionImagePath(imageSlug string) string {
+	return fmt.Sprintf("factions/%s.png", imageSlug)
+}
+
+// SkillImagePath returns the path of the image of a skill, imageSlug is set by Skill.Sanitize
+func SkillImagePath(imageSlug string) string {
+	return fmt.Sprintf("hashed-img/%s.png", imageSlug)
+}
+
+// StatusEffectImagePath returns the path of the image of a status effect, imageSlug is set by
+// StatusEffect.Sanitize
+func StatusEffectImagePath(imageSlug string) string {
+	return fmt.Sprintf("status-effects/%s.png", imageSlug)
+}
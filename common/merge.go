@@ -0,0 +1,278 @@
+package common
+
+import (
+	"encoding/json"
+	"log"
+	"os"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/raid-codex/tools/utils"
+)
+
+// MergePolicy orders sources by trust for each field.
+// Keys of Priorities are field prefixes (see FieldProvenance.Field), the longest matching
+// prefix wins. Sources are listed from the most to the least trusted, unlisted sources
+// rank after every listed one.
+type MergePolicy struct {
+	Priorities map[string][]string `json:"priorities"`
+	Default    []string            `json:"default"`
+	// Unrecorded lists the field prefixes whose values without provenance may be replaced, the values
+	// of other fields predating provenance are considered curated
+	Unrecorded []string `json:"unrecorded"`
+}
+
+var DefaultMergePolicy = &MergePolicy{
+	Priorities: map[string][]string{
+		"characteristics": []string{
+			ProvenanceSource_Manual,
+			ProvenanceSource_Ayumilove,
+			ProvenanceSource_Wikia,
+			ProvenanceSource_CharacteristicsSheet,
+		},
+		"skills": []string{
+			ProvenanceSource_Manual,
+			ProvenanceSource_Ayumilove,
+			ProvenanceSource_RaidShadowLegendsPro,
+			ProvenanceSource_CharacteristicsSheet,
+			ProvenanceSource_FullSheet,
+		},
+		"auras": []string{
+			ProvenanceSource_Manual,
+			ProvenanceSource_FullSheet,
+			ProvenanceSource_Ayumilove,
+			ProvenanceSource_RaidShadowLegendsPro,
+			ProvenanceSource_Gameronion,
+			ProvenanceSource_CharacteristicsSheet,
+		},
+	},
+	Default: []string{
+		ProvenanceSource_Manual,
+		ProvenanceSource_FullSheet,
+		ProvenanceSource_CharacteristicsSheet,
+		ProvenanceSource_TierListSheet,
+		ProvenanceSource_Ayumilove,
+		ProvenanceSource_RaidShadowLegendsPro,
+		ProvenanceSource_Wikia,
+		ProvenanceSource_Gameronion,
+		ProvenanceSource_Hellhades,
+	},
+}
+
+// LoadMergePolicy reads a policy from filename, DefaultMergePolicy is used when filename is empty
+func LoadMergePolicy(filename string) (*MergePolicy, error) {
+	if filename == "" {
+		return DefaultMergePolicy, nil
+	}
+	file, errOpen := os.Open(filename)
+	if errOpen != nil {
+		return nil, errors.Annotate(errOpen, "cannot open merge policy")
+	}
+	defer file.Close()
+	var policy MergePolicy
+	errJSON := json.NewDecoder(file).Decode(&policy)
+	if errJSON != nil {
+		return nil, errors.Annotate(errJSON, "cannot unmarshal merge policy")
+	}
+	return &policy, nil
+}
+
+func (p *MergePolicy) sourcesFor(field string) []string {
+	best := ""
+	sources := p.Default
+	for prefix, list := range p.Priorities {
+		if (field == prefix || strings.HasPrefix(field, prefix+".")) && len(prefix) > len(best) {
+			best = prefix
+			sources = list
+		}
+	}
+	return sources
+}
+
+// AcceptsUnrecorded tells whether a value of field without provenance may be replaced
+func (p *MergePolicy) AcceptsUnrecorded(field string) bool {
+	for _, prefix := range p.Unrecorded {
+		if field == prefix || strings.HasPrefix(field, prefix+".") {
+			return true
+		}
+	}
+	return false
+}
+
+// Rank returns the position of source for field, lower is more trusted
+func (p *MergePolicy) Rank(field, source string) int {
+	sources := p.sourcesFor(field)
+	for idx, s := range sources {
+		if s == source {
+			return idx
+		}
+	}
+	return len(sources)
+}
+
+const (
+	ConflictReason_Locked        = "locked"
+	ConflictReason_NoProvenance  = "no-provenance"
+	ConflictReason_LowerPriority = "lower-priority"
+	ConflictReason_SamePriority  = "same-priority"
+)
+
+// Conflict is an incoming value that was not applied because it disagrees with a more trusted one
+type Conflict struct {
+	Champion       string      `json:"champion"`
+	Field          string      `json:"field"`
+	Reason         string      `json:"reason"`
+	CurrentSource  string      `json:"current_source"`
+	CurrentValue   interface{} `json:"current_value"`
+	IncomingSource string      `json:"incoming_source"`
+	IncomingURL    string      `json:"incoming_url"`
+	IncomingValue  interface{} `json:"incoming_value"`
+	Date           string      `json:"date"`
+}
+
+func (c *Conflict) key() string {
+	return strings.Join([]string{c.Champion, c.Field, c.IncomingSource}, "|")
+}
+
+// ReviewQueue holds conflicts waiting for a human decision, it is persisted as a JSON file
+type ReviewQueue struct {
+	filename  string
+	Conflicts []*Conflict `json:"conflicts"`
+}
+
+// LoadReviewQueue reads the queue stored in filename, a missing file gives an empty queue
+func LoadReviewQueue(filename string) (*ReviewQueue, error) {
+	queue := &ReviewQueue{filename: filename, Conflicts: make([]*Conflict, 0)}
+	file, errOpen := os.Open(filename)
+	if errOpen != nil && os.IsNotExist(errOpen) {
+		return queue, nil
+	} else if errOpen != nil {
+		return nil, errors.Annotate(errOpen, "cannot open review queue")
+	}
+	defer file.Close()
+	errJSON := json.NewDecoder(file).Decode(queue)
+	if errJSON != nil {
+		return nil, errors.Annotate(errJSON, "cannot unmarshal review queue")
+	}
+	return queue, nil
+}
+
+// Add queues conflict, replacing a previous one for the same champion, field and source
+func (q *ReviewQueue) Add(conflict *Conflict) {
+	for idx, c := range q.Conflicts {
+		if c.key() == conflict.key() {
+			q.Conflicts[idx] = conflict
+			return
+		}
+	}
+	q.Conflicts = append(q.Conflicts, conflict)
+}
+
+func (q *ReviewQueue) Save() error {
+	return utils.WriteToFile(q.filename, q)
+}
+
+// Merger decides whether a value coming from a source may replace the current one
+type Merger struct {
+	Policy *MergePolicy
+	Queue  *ReviewQueue
+}
+
+func NewMerger(policyFile, queueFile string) (*Merger, error) {
+	policy, errPolicy := LoadMergePolicy(policyFile)
+	if errPolicy != nil {
+		return nil, errPolicy
+	}
+	queue, errQueue := LoadReviewQueue(queueFile)
+	if errQueue != nil {
+		return nil, errQueue
+	}
+	return &Merger{Policy: policy, Queue: queue}, nil
+}
+
+// Accept tells whether incoming may replace current for field of champion.
+// When it does, provenance of the field is updated and the caller must store incoming.
+// When it does not, the disagreement is added to the review queue. Values without provenance
+// predate it and are only replaced in the fields the policy lists as unrecorded.
+// A nil merger accepts every value.
+func (m *Merger) Accept(champion *Champion, field, source, url string, current, incoming interface{}) bool {
+	if m == nil {
+		champion.SetProvenance(field, source, url)
+		return true
+	}
+	locked := champion.LockedBy(field)
+	existing := champion.provenanceOf(field)
+	if reflect.DeepEqual(current, incoming) {
+		if locked == nil && (existing == nil || m.Policy.Rank(field, source) <= m.Policy.Rank(field, existing.Source)) {
+			champion.SetProvenance(field, source, url)
+		}
+		return true
+	}
+	reason := ""
+	switch true {
+	case locked != nil:
+		reason = ConflictReason_Locked
+	case current == nil || reflect.ValueOf(current).IsZero():
+		// nothing to lose
+	case existing == nil:
+		if !m.Policy.AcceptsUnrecorded(field) {
+			reason = ConflictReason_NoProvenance
+		}
+	case existing.Source == source:
+		// a source may always update its own value
+	case m.Policy.Rank(field, source) > m.Policy.Rank(field, existing.Source):
+		reason = ConflictReason_LowerPriority
+	case m.Policy.Rank(field, source) == m.Policy.Rank(field, existing.Source):
+		reason = ConflictReason_SamePriority
+	}
+	if reason == "" {
+		champion.SetProvenance(field, source, url)
+		return true
+	}
+	log.Printf("conflict on %s for %s: %s from %s not applied (%s)\n", field, champion.Slug, source, url, reason)
+	currentSource := ""
+	if locked != nil {
+		currentSource = locked.Source
+	} else if existing != nil {
+		currentSource = existing.Source
+	}
+	m.Queue.Add(&Conflict{
+		Champion:       champion.Slug,
+		Field:          field,
+		Reason:         reason,
+		CurrentSource:  currentSource,
+		CurrentValue:   current,
+		IncomingSource: source,
+		IncomingURL:    url,
+		IncomingValue:  incoming,
+		Date:           time.Now().Format(time.RFC3339),
+	})
+	return false
+}
+
+// MergeRawSkill parses raw aside so that each skill description only replaces the current one
+// when merger allows it. A nil merger accepts every value.
+func (c *Champion) MergeRawSkill(merger *Merger, raw, source, url string) error {
+	parsed := &Champion{}
+	errParse := parsed.ParseRawSkill(raw)
+	if errParse != nil {
+		return errParse
+	}
+	if len(parsed.Auras) > 0 && merger.Accept(c, "auras", source, url, c.Auras, parsed.Auras) {
+		c.Auras = parsed.Auras
+	}
+	for _, incoming := range parsed.Skills {
+		field := SkillProvenanceField(incoming.Name, "raw_description")
+		skill, errSkill := c.GetSkillByName(incoming.Name)
+		if errSkill != nil {
+			if merger.Accept(c, field, source, url, "", incoming.RawDescription) {
+				c.Skills = append(c.Skills, incoming)
+			}
+		} else if merger.Accept(c, field, source, url, skill.RawDescription, incoming.RawDescription) {
+			skill.RawDescription = incoming.RawDescription
+		}
+	}
+	return nil
+}
@@ -15,4 +15,5 @@ type Paged interface {
 	GetPageContent_Templates(*template.Template, io.Writer, map[string]interface{}) error
 	GetPageExcerpt() string
 	GetPageExtraData(string) (map[string]interface{}, error)
+	GetWebsiteLink() string
 }
@@ -0,0 +1,142 @@
+package common
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// FieldProvenance records which source last set a field of a champion, and when.
+// Field is a dotted path following the json tags of Champion, e.g. "characteristics.60"
+// or "skills.<skill-slug>.raw_description".
+// A locked field holds a curated value that the Merger never overwrites.
+type FieldProvenance struct {
+	Field  string `json:"field"`
+	Source string `json:"source"`
+	URL    string `json:"url"`
+	Date   string `json:"date"`
+	Locked bool   `json:"locked,omitempty"`
+}
+
+const (
+	ProvenanceSource_Ayumilove            = "ayumilove.net"
+	ProvenanceSource_Gameronion           = "gameronion.com"
+	ProvenanceSource_RaidShadowLegendsPro = "raidshadowlegends.pro"
+	ProvenanceSource_Hellhades            = "hellhades.com"
+	ProvenanceSource_Wikia                = "raid-shadow-legends.fandom.com"
+	ProvenanceSource_FullSheet            = "full-sheet"
+	ProvenanceSource_TierListSheet        = "tier-list-sheet"
+	ProvenanceSource_CharacteristicsSheet = "characteristics-sheet"
+	ProvenanceSource_Manual               = "manual"
+)
+
+func (c *Champion) SetProvenance(field, source, url string) {
+	date := time.Now().Format(time.RFC3339)
+	for _, p := range c.Provenance {
+		if p.Field == field {
+			p.Source = source
+			p.URL = url
+			p.Date = date
+			return
+		}
+	}
+	c.Provenance = append(c.Provenance, &FieldProvenance{
+		Field:  field,
+		Source: source,
+		URL:    url,
+		Date:   date,
+	})
+}
+
+func (c *Champion) GetProvenance(field string) *FieldProvenance {
+	for _, p := range c.Provenance {
+		if p.Field == field {
+			return p
+		}
+	}
+	return nil
+}
+
+// provenanceOf returns the record of field, or of the closest field containing it, e.g. the
+// record of characteristics.60 for characteristics.60.hp
+func (c *Champion) provenanceOf(field string) *FieldProvenance {
+	var found *FieldProvenance
+	for _, p := range c.Provenance {
+		if p.Field == field {
+			return p
+		} else if strings.HasPrefix(field, p.Field+".") && (found == nil || len(p.Field) > len(found.Field)) {
+			found = p
+		}
+	}
+	return found
+}
+
+// LockedBy returns the locked record protecting field: a lock on a field protects its sub-fields,
+// and a locked sub-field protects the fields containing it from being replaced as a whole
+func (c *Champion) LockedBy(field string) *FieldProvenance {
+	for _, p := range c.Provenance {
+		if !p.Locked {
+			continue
+		}
+		if p.Field == field || strings.HasPrefix(field, p.Field+".") || strings.HasPrefix(p.Field, field+".") {
+			return p
+		}
+	}
+	return nil
+}
+
+// LockProvenance marks field and its sub-fields as manually curated, the record is created if the
+// field has none
+func (c *Champion) LockProvenance(field string) {
+	p := c.GetProvenance(field)
+	if p == nil {
+		c.SetProvenance(field, ProvenanceSource_Manual, "")
+		p = c.GetProvenance(field)
+	}
+	p.Locked = true
+}
+
+func (c *Champion) UnlockProvenance(field string) error {
+	p := c.GetProvenance(field)
+	if p == nil {
+		return fmt.Errorf("no provenance recorded for field %s of %s", field, c.Name)
+	}
+	p.Locked = false
+	return nil
+}
+
+// GetProvenanceWithPrefix returns every record whose field is prefix or a sub-field of prefix
+func (c *Champion) GetProvenanceWithPrefix(prefix string) []*FieldProvenance {
+	if prefix == "" {
+		return c.Provenance
+	}
+	found := make([]*FieldProvenance, 0)
+	for _, p := range c.Provenance {
+		if p.Field == prefix || strings.HasPrefix(p.Field, prefix+".") {
+			found = append(found, p)
+		}
+	}
+	return found
+}
+
+func (c *Champion) sanitizeProvenance() error {
+	if c.Provenance == nil {
+		c.Provenance = make([]*FieldProvenance, 0)
+	}
+	for _, p := range c.Provenance {
+		if p.Field == "" || p.Source == "" {
+			return fmt.Errorf("invalid provenance record %+v for champion %s", p, c.Name)
+		}
+	}
+	sort.SliceStable(c.Provenance, func(i, j int) bool { return c.Provenance[i].Field < c.Provenance[j].Field })
+	return nil
+}
+
+func SkillProvenanceField(skillName, field string) string {
+	return fmt.Sprintf("skills.%s.%s", GetLinkNameFromSanitizedName(skillName), field)
+}
+
+func RatingSourceProvenanceField(source string) string {
+	return fmt.Sprintf("all_ratings.%s", source)
+}
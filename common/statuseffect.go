@@ -318,6 +318,13 @@ func (se *StatusEffect) GetPageContent_Templates(tmpl *template.Template, output
 
 func (se StatusEffect) GetPageExcerpt() string { return se.RawDescription }
 
+func (se StatusEffect) GetWebsiteLink() string {
+	if se.WebsiteLink == "" {
+		return fmt.Sprintf("/effects/%s", strings.TrimSuffix(se.Slug, "-2"))
+	}
+	return se.WebsiteLink
+}
+
 func (se *StatusEffect) GetPageExtraData(dataDirectory string) (map[string]interface{}, error) {
 	data := map[string]interface{}{}
 
@@ -387,6 +394,11 @@ func (se *StatusEffect) GetPageExtraData(dataDirectory string) (map[string]inter
 
 	data["AvailableChampions"] = matching
 
+	structuredData, errStructuredData := se.StructuredData(DefaultWebsiteRoot)
+	if errStructuredData != nil {
+		return nil, errStructuredData
+	}
+	data["StructuredData"] = structuredData
 	return data, nil
 }
 
@@ -0,0 +1,211 @@
+package common
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/raid-codex/tools/seo/structureddata"
+)
+
+// DefaultWebsiteRoot is the root of the absolute urls of the structured data of the pages
+// published to WordPress
+const DefaultWebsiteRoot = "https://raid-codex.com"
+
+var (
+	game = structureddata.Node{"@type": "VideoGame", "name": "RAID: Shadow Legends"}
+)
+
+func home(root string) structureddata.Crumb {
+	return structureddata.Crumb{Name: "RAID - Codex", URL: root + "/"}
+}
+
+// StructuredData returns the validated JSON-LD documents of the champion page: the champion,
+// its breadcrumbs and its videos. Urls are absolute, under root.
+func (c *Champion) StructuredData(root string) ([]json.RawMessage, error) {
+	link := root + c.GetWebsiteLink()
+	champion := structureddata.Node{
+		"@type":       "Thing",
+		"name":        c.Name,
+		"url":         link,
+		"description": fmt.Sprintf("Member of the faction %s, %s is a champion of %s rarity and of %s type", c.Faction.Name, c.Name, c.Rarity, c.Type),
+		"subjectOf":   game,
+	}
+	if c.Slug != "" {
+		champion["image"] = root + UploadsPath + ChampionImagePath(c.Slug)
+	}
+	nodes := []structureddata.Node{
+		champion,
+		structureddata.Breadcrumbs(home(root), structureddata.Crumb{Name: "Champions", URL: root + "/champions/"}, structureddata.Crumb{Name: c.Name, URL: link}),
+	}
+	for _, video := range c.Videos {
+		if node := videoObject(c, video, link); node != nil {
+			nodes = append(nodes, node)
+		}
+	}
+	return structureddata.Marshal(nodes...)
+}
+
+// videoObject describes a video, only dated youtube videos have the thumbnail and upload date
+// search engines require, link is the url of the champion page
+func videoObject(c *Champion, video *Video, link string) structureddata.Node {
+	if video.Source != "youtube" || video.ID == "" || video.DateAdded == "" {
+		return nil
+	}
+	node := structureddata.Node{
+		"@type":        "VideoObject",
+		"name":         fmt.Sprintf("%s - RAID: Shadow Legends", c.Name),
+		"description":  fmt.Sprintf("Video about %s", c.Name),
+		"thumbnailUrl": fmt.Sprintf("https://i.ytimg.com/vi/%s/hqdefault.jpg", video.ID),
+		"embedUrl":     fmt.Sprintf("https://www.youtube.com/embed/%s", video.ID),
+		"contentUrl":   fmt.Sprintf("https://www.youtube.com/watch?v=%s", video.ID),
+		"uploadDate":   video.DateAdded,
+		"about":        structureddata.Node{"@type": "Thing", "name": c.Name, "url": link},
+	}
+	if video.Author != "" {
+		node["description"] = fmt.Sprintf("Video about %s by %s", c.Name, video.Author)
+		node["author"] = structureddata.Node{"@type": "Person", "name": video.Author}
+	}
+	return node
+}
+
+// StructuredData returns the validated JSON-LD documents of the faction page: the faction, its
+// breadcrumbs and its roster. Urls are absolute, under root.
+func (f *Faction) StructuredData(root string) ([]json.RawMessage, error) {
+	link := root + f.GetWebsiteLink()
+	faction := structureddata.Node{
+		"@type":     "Organization",
+		"name":      f.Name,
+		"url":       link,
+		"subjectOf": game,
+	}
+	if f.ImageSlug != "" {
+		faction["image"] = root + UploadsPath + FactionImagePath(f.ImageSlug)
+	}
+	if f.RawDescription != "" {
+		faction["description"] = f.RawDescription
+	}
+	champions, errChampions := GetChampions(FilterChampionFactionSlug(f.Slug))
+	if errChampions != nil {
+		return nil, errChampions
+	}
+	roster := make([]structureddata.Crumb, len(champions))
+	for idx, champion := range champions {
+		roster[idx] = structureddata.Crumb{Name: champion.Name, URL: root + champion.GetWebsiteLink()}
+	}
+	return structureddata.Marshal(
+		faction,
+		structureddata.Breadcrumbs(home(root), structureddata.Crumb{Name: "Factions", URL: root + "/factions/"}, structureddata.Crumb{Name: f.Name, URL: link}),
+		structureddata.ItemList(fmt.Sprintf("Champions of %s", f.Name), "https://schema.org/ItemListOrderAscending", roster...),
+	)
+}
+
+// StructuredData returns the validated JSON-LD documents of the status effect page, with absolute
+// urls under root
+func (se *StatusEffect) StructuredData(root string) ([]json.RawMessage, error) {
+	link := root + se.GetWebsiteLink()
+	term := structureddata.Node{
+		"@type":    "DefinedTerm",
+		"name":     se.Type,
+		"url":      link,
+		"termCode": se.Slug,
+		"inDefinedTermSet": structureddata.Node{
+			"@type": "DefinedTermSet",
+			"name":  "RAID: Shadow Legends status effects",
+			"url":   root + "/effects/",
+		},
+	}
+	if se.RawDescription != "" {
+		term["description"] = se.RawDescription
+	}
+	return structureddata.Marshal(
+		term,
+		structureddata.Breadcrumbs(home(root), structureddata.Crumb{Name: "Status effects", URL: root + "/effects/"}, structureddata.Crumb{Name: se.Type, URL: link}),
+	)
+}
+
+// StructuredData returns the validated JSON-LD documents of the fusion page, an event is only
+// described when the fusion has a start date. Urls are absolute, under root.
+func (f *Fusion) StructuredData(root string) ([]json.RawMessage, error) {
+	link := root + f.GetWebsiteLink()
+	nodes := []structureddata.Node{
+		structureddata.Breadcrumbs(home(root), structureddata.Crumb{Name: "Fusions", URL: root + "/fusions/"}, structureddata.Crumb{Name: f.Name, URL: link}),
+	}
+	if f.TimeStart != nil {
+		event := structureddata.Node{
+			"@type":               "Event",
+			"name":                fmt.Sprintf("Fusion - %s", f.Name),
+			"url":                 link,
+			"startDate":           f.TimeStart.Format(time.RFC3339),
+			"eventStatus":         "https://schema.org/EventScheduled",
+			"eventAttendanceMode": "https://schema.org/OnlineEventAttendanceMode",
+			"location":            structureddata.Node{"@type": "VirtualLocation", "url": link},
+			"about":               game,
+		}
+		if f.TimeEnd != nil {
+			event["endDate"] = f.TimeEnd.Format(time.RFC3339)
+		}
+		if f.Schedule != nil {
+			subEvents := make([]structureddata.Node, 0, len(f.Schedule.Raw))
+			for _, item := range f.Schedule.Raw {
+				if item.DateStart == "" {
+					continue
+				}
+				subEvent := structureddata.Node{
+					"@type":     "Event",
+					"name":      item.Name,
+					"startDate": item.DateStart,
+					"location":  structureddata.Node{"@type": "VirtualLocation", "url": link},
+				}
+				if item.DateEnd != "" {
+					subEvent["endDate"] = item.DateEnd
+				}
+				subEvents = append(subEvents, subEvent)
+			}
+			if len(subEvents) > 0 {
+				event["subEvent"] = subEvents
+			}
+		}
+		nodes = append(nodes, event)
+	}
+	return structureddata.Marshal(nodes...)
+}
+
+// TierListStructuredData returns the champions as an ItemList ranked by overall grade, best first,
+// with absolute urls under root
+func TierListStructuredData(name, root string, champions ChampionList) ([]json.RawMessage, error) {
+	ranked := make(ChampionList, len(champions))
+	copy(ranked, champions)
+	overall := func(c *Champion) int {
+		if c.Rating == nil {
+			return -1
+		}
+		if rank, ok := rankToInt[c.Rating.Overall]; ok {
+			return rank
+		}
+		return -1
+	}
+	sort.SliceStable(ranked, func(i, j int) bool { return overall(ranked[i]) > overall(ranked[j]) })
+	items := make([]structureddata.Crumb, len(ranked))
+	for idx, champion := range ranked {
+		items[idx] = structureddata.Crumb{Name: champion.Name, URL: root + champion.GetWebsiteLink()}
+	}
+	return structureddata.Marshal(structureddata.ItemList(name, "https://schema.org/ItemListOrderDescending", items...))
+}
+
+// PageStructuredData returns the JSON-LD documents of the page of p with absolute urls under root,
+// nil for pages that are not the page of an entity
+func PageStructuredData(p interface{}, root string) ([]json.RawMessage, error) {
+	switch entity := p.(type) {
+	case *Champion:
+		return entity.StructuredData(root)
+	case *Faction:
+		return entity.StructuredData(root)
+	case *StatusEffect:
+		return entity.StructuredData(root)
+	case *Fusion:
+		return entity.StructuredData(root)
+	}
+	return nil, nil
+}
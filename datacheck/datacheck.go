@@ -0,0 +1,343 @@
+package datacheck
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/raid-codex/tools/common"
+)
+
+// BrokenLink is a reference to something that does not exist in the data directory
+type BrokenLink struct {
+	// Filename is relative to the data directory
+	Filename string
+	// Path is the JSON path of the reference in the file
+	Path string
+	// Kind is what the reference points to (champion, faction, fusion, status effect, mastery, image)
+	Kind   string
+	Target string
+	// Reason is set when the target exists but the reference is still wrong
+	Reason string
+}
+
+func (bl *BrokenLink) String() string {
+	reason := bl.Reason
+	if reason == "" {
+		reason = fmt.Sprintf("unknown %s", bl.Kind)
+	}
+	return fmt.Sprintf("%s: %s: %s %q", bl.Filename, bl.Path, reason, bl.Target)
+}
+
+const (
+	Kind_Champion     = "champion"
+	Kind_Faction      = "faction"
+	Kind_Fusion       = "fusion"
+	Kind_StatusEffect = "status effect"
+	Kind_Mastery      = "mastery"
+	Kind_Image        = "image"
+)
+
+// Checker verifies every cross-reference between the files of a data directory
+type Checker struct {
+	DataDirectory string
+	// ImagesDirectory, when set, is searched for a file named after each image slug
+	ImagesDirectory string
+
+	champions     map[string]*common.Champion
+	factions      map[string]*common.Faction
+	statusEffects map[string]*common.StatusEffect
+	fusions       map[string]*common.Fusion
+	masteries     map[string]*common.Mastery
+	locations     map[interface{}]location
+	images        map[string]bool
+	links         []*BrokenLink
+}
+
+// Check loads the data directory and returns every broken link, sorted by file
+func (c *Checker) Check() ([]*BrokenLink, error) {
+	if errLoad := c.load(); errLoad != nil {
+		return nil, errLoad
+	}
+	c.links = make([]*BrokenLink, 0)
+	for _, champion := range c.champions {
+		c.checkChampion(champion)
+	}
+	for _, faction := range c.factions {
+		c.checkFaction(faction)
+	}
+	for _, se := range c.statusEffects {
+		c.checkStatusEffect(se)
+	}
+	for _, fusion := range c.fusions {
+		c.checkFusion(fusion)
+	}
+	for _, mastery := range c.masteries {
+		c.checkImage(mastery, "$.image_slug", mastery.ImageSlug, "")
+	}
+	sort.SliceStable(c.links, func(i, j int) bool {
+		if c.links[i].Filename == c.links[j].Filename {
+			return c.links[i].Path < c.links[j].Path
+		}
+		return c.links[i].Filename < c.links[j].Filename
+	})
+	return c.links, nil
+}
+
+func (c *Checker) load() error {
+	c.champions = map[string]*common.Champion{}
+	c.factions = map[string]*common.Faction{}
+	c.statusEffects = map[string]*common.StatusEffect{}
+	c.fusions = map[string]*common.Fusion{}
+	c.masteries = map[string]*common.Mastery{}
+	c.locations = map[interface{}]location{}
+	steps := []struct {
+		directory string
+		decode    func(loc location, content []byte) error
+	}{
+		{"champions", func(loc location, content []byte) error {
+			var champion common.Champion
+			if err := json.Unmarshal(content, &champion); err != nil {
+				return err
+			}
+			c.champions[champion.Slug] = &champion
+			c.locations[&champion] = loc
+			return nil
+		}},
+		{"factions", func(loc location, content []byte) error {
+			var faction common.Faction
+			if err := json.Unmarshal(content, &faction); err != nil {
+				return err
+			}
+			c.factions[faction.Slug] = &faction
+			c.locations[&faction] = loc
+			return nil
+		}},
+		{"status-effects", func(loc location, content []byte) error {
+			var se common.StatusEffect
+			if err := json.Unmarshal(content, &se); err != nil {
+				return err
+			}
+			c.statusEffects[se.Slug] = &se
+			c.locations[&se] = loc
+			return nil
+		}},
+		{"fusions", func(loc location, content []byte) error {
+			var fusion common.Fusion
+			if err := json.Unmarshal(content, &fusion); err != nil {
+				return err
+			}
+			c.fusions[fusion.Slug] = &fusion
+			c.locations[&fusion] = loc
+			return nil
+		}},
+		{"masteries", func(loc location, content []byte) error {
+			var mastery common.Mastery
+			if err := json.Unmarshal(content, &mastery); err != nil {
+				return err
+			}
+			c.masteries[mastery.Slug] = &mastery
+			c.locations[&mastery] = loc
+			return nil
+		}},
+	}
+	for _, step := range steps {
+		if err := c.readDirectory(step.directory, step.decode); err != nil {
+			return err
+		}
+	}
+	return c.loadImages()
+}
+
+// location is where an entity was read, Prefix is its JSON path in the file
+type location struct {
+	Filename string
+	Prefix   string
+}
+
+// readDirectory decodes every file of docs/<directory>/current but the index, or the entries of the
+// index when the directory has no other file
+func (c *Checker) readDirectory(directory string, decode func(loc location, content []byte) error) error {
+	relDirectory := filepath.Join("docs", directory, "current")
+	files, errDir := ioutil.ReadDir(filepath.Join(c.DataDirectory, relDirectory))
+	if errDir != nil {
+		return errDir
+	}
+	found := false
+	for _, file := range files {
+		if file.IsDir() || file.Name() == "index.json" || !strings.HasSuffix(file.Name(), ".json") {
+			continue
+		}
+		found = true
+		filename := filepath.Join(relDirectory, file.Name())
+		content, errRead := ioutil.ReadFile(filepath.Join(c.DataDirectory, filename))
+		if errRead != nil {
+			return errRead
+		}
+		if errDecode := decode(location{Filename: filename, Prefix: "$"}, content); errDecode != nil {
+			return errors.Annotatef(errDecode, "cannot read %s", filename)
+		}
+	}
+	if found {
+		return nil
+	}
+	filename := filepath.Join(relDirectory, "index.json")
+	content, errRead := ioutil.ReadFile(filepath.Join(c.DataDirectory, filename))
+	if errRead != nil {
+		return errRead
+	}
+	var entries []json.RawMessage
+	if errJSON := json.Unmarshal(content, &entries); errJSON != nil {
+		return errors.Annotatef(errJSON, "cannot read %s", filename)
+	}
+	for idx, entry := range entries {
+		if errDecode := decode(location{Filename: filename, Prefix: fmt.Sprintf("$[%d]", idx)}, entry); errDecode != nil {
+			return errors.Annotatef(errDecode, "cannot read %s entry %d", filename, idx)
+		}
+	}
+	return nil
+}
+
+func (c *Checker) loadImages() error {
+	c.images = map[string]bool{}
+	if c.ImagesDirectory == "" {
+		return nil
+	}
+	return filepath.Walk(c.ImagesDirectory, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() {
+			c.images[strings.TrimSuffix(info.Name(), filepath.Ext(info.Name()))] = true
+		}
+		return nil
+	})
+}
+
+func (c *Checker) broken(owner interface{}, path, kind, target string) {
+	c.add(owner, &BrokenLink{Path: path, Kind: kind, Target: target})
+}
+
+// add sets the file of link and prefixes its path, paths are written relative to the entity ("$.slug")
+func (c *Checker) add(owner interface{}, link *BrokenLink) {
+	loc := c.locations[owner]
+	link.Filename = loc.Filename
+	link.Path = loc.Prefix + strings.TrimPrefix(link.Path, "$")
+	c.links = append(c.links, link)
+}
+
+func (c *Checker) champion(owner interface{}, path, slug string) {
+	if _, ok := c.champions[slug]; !ok {
+		c.broken(owner, path, Kind_Champion, slug)
+	}
+}
+
+func (c *Checker) effect(owner interface{}, path, slug string) {
+	if _, ok := c.statusEffects[slug]; !ok {
+		c.broken(owner, path, Kind_StatusEffect, slug)
+	}
+}
+
+func (c *Checker) fusion(owner interface{}, path, slug string) {
+	if _, ok := c.fusions[slug]; !ok {
+		c.broken(owner, path, Kind_Fusion, slug)
+	}
+}
+
+// checkImage verifies that an image slug is the one Sanitize derives, when expected is set, and
+// that the image exists when an images directory is given
+func (c *Checker) checkImage(owner interface{}, path, slug, expected string) {
+	if slug == "" {
+		return
+	}
+	if expected != "" && slug != expected {
+		c.add(owner, &BrokenLink{
+			Path: path, Kind: Kind_Image, Target: slug,
+			Reason: fmt.Sprintf("image slug should be %s, not", expected),
+		})
+	}
+	if c.ImagesDirectory != "" && !c.images[slug] {
+		c.broken(owner, path, Kind_Image, slug)
+	}
+}
+
+func (c *Checker) checkChampion(champion *common.Champion) {
+	if _, ok := c.factions[champion.FactionSlug]; !ok {
+		c.broken(champion, "$.faction_slug", Kind_Faction, champion.FactionSlug)
+	}
+	c.checkImage(champion, "$.image_slug", champion.ImageSlug, fmt.Sprintf("image-champion-%s", champion.Slug))
+	for idx, slug := range champion.EffectSlugs {
+		c.effect(champion, fmt.Sprintf("$.effect_slugs[%d]", idx), slug)
+	}
+	for idx, skill := range champion.Skills {
+		for effectIdx, effect := range skill.Effects {
+			c.effect(champion, fmt.Sprintf("$.skills[%d].effects[%d].slug", idx, effectIdx), effect.Slug)
+		}
+		for upgradeIdx, upgrade := range skill.Upgrades {
+			for effectIdx, effect := range upgrade.Effects {
+				c.effect(champion, fmt.Sprintf("$.skills[%d].upgrades[%d].effects[%d].slug", idx, upgradeIdx, effectIdx), effect.Slug)
+			}
+		}
+	}
+	for idx, aura := range champion.Auras {
+		for effectIdx, effect := range aura.Effects {
+			c.effect(champion, fmt.Sprintf("$.auras[%d].effects[%d].slug", idx, effectIdx), effect.Slug)
+		}
+	}
+	for idx, synergy := range champion.Synergies {
+		for slugIdx, slug := range synergy.Champions {
+			c.champion(champion, fmt.Sprintf("$.synergy[%d].champions[%d]", idx, slugIdx), slug)
+		}
+	}
+	for idx, masteries := range champion.Masteries {
+		for tree, slugs := range map[string][]string{"offense": masteries.Offense, "defense": masteries.Defense, "support": masteries.Support} {
+			for slugIdx, slug := range slugs {
+				if _, ok := c.masteries[slug]; !ok {
+					c.broken(champion, fmt.Sprintf("$.masteries[%d].%s[%d]", idx, tree, slugIdx), Kind_Mastery, slug)
+				}
+			}
+		}
+	}
+	for idx, data := range champion.FusionData {
+		c.fusion(champion, fmt.Sprintf("$.fusion_data[%d].fusion_slug", idx), data.FusionSlug)
+	}
+}
+
+func (c *Checker) checkFaction(faction *common.Faction) {
+	c.checkImage(faction, "$.image_slug", faction.ImageSlug, fmt.Sprintf("image-faction-%s", faction.Slug))
+	for idx, slug := range faction.ChampionSlugs {
+		c.champion(faction, fmt.Sprintf("$.champion_slugs[%d]", idx), slug)
+	}
+}
+
+func (c *Checker) checkStatusEffect(se *common.StatusEffect) {
+	c.checkImage(se, "$.image_slug", se.ImageSlug, "")
+	for idx, slug := range se.ChampionSlugs {
+		c.champion(se, fmt.Sprintf("$.champion_slugs[%d]", idx), slug)
+	}
+}
+
+func (c *Checker) checkFusion(fusion *common.Fusion) {
+	c.champion(fusion, "$.champion_slug", fusion.ChampionSlug)
+	if fusion.ParentFusionSlug != nil {
+		c.fusion(fusion, "$.parent_fusion_slug", *fusion.ParentFusionSlug)
+	}
+	for idx, ingredient := range fusion.Ingredients {
+		c.champion(fusion, fmt.Sprintf("$.ingredients[%d].champion_slug", idx), ingredient.ChampionSlug)
+		if ingredient.FusionSlug != nil {
+			c.fusion(fusion, fmt.Sprintf("$.ingredients[%d].fusion_slug", idx), *ingredient.FusionSlug)
+		}
+	}
+	if fusion.Schedule != nil {
+		for idx, item := range fusion.Schedule.Raw {
+			for slugIdx, slug := range item.ChampionSlugs {
+				c.champion(fusion, fmt.Sprintf("$.schedule.raw[%d].champion_slugs[%d]", idx, slugIdx), slug)
+			}
+		}
+	}
+}
@@ -0,0 +1,253 @@
+-- raid-codex SQLite export, schema version 1.
+-- Tables and columns are only ever added within a version; renaming or removing
+-- anything requires a new schema file and a bump of SQLiteSchemaVersion.
+
+CREATE TABLE schema_info (
+    key   TEXT PRIMARY KEY,
+    value TEXT NOT NULL
+);
+
+CREATE TABLE factions (
+    slug                TEXT PRIMARY KEY,
+    name                TEXT NOT NULL,
+    date_added          TEXT,
+    website_link        TEXT,
+    image_slug          TEXT,
+    number_of_champions INTEGER,
+    description         TEXT
+);
+
+CREATE TABLE champions (
+    slug           TEXT PRIMARY KEY,
+    name           TEXT NOT NULL,
+    faction_slug   TEXT REFERENCES factions (slug),
+    rarity         TEXT,
+    element        TEXT,
+    type           TEXT,
+    date_added     TEXT,
+    website_link   TEXT,
+    image_slug     TEXT,
+    thumbnail      TEXT,
+    lore           TEXT,
+    overall_rating TEXT,
+    review_count   INTEGER
+);
+
+CREATE TABLE champion_characteristics (
+    champion_slug   TEXT NOT NULL REFERENCES champions (slug),
+    level           INTEGER NOT NULL,
+    hp              INTEGER,
+    attack          INTEGER,
+    defense         INTEGER,
+    speed           INTEGER,
+    critical_rate   REAL,
+    critical_damage REAL,
+    resistance      INTEGER,
+    accuracy        INTEGER,
+    PRIMARY KEY (champion_slug, level)
+);
+
+CREATE TABLE champion_tags (
+    champion_slug TEXT NOT NULL REFERENCES champions (slug),
+    tag           TEXT NOT NULL,
+    PRIMARY KEY (champion_slug, tag)
+);
+
+-- one row per rating location and source; source "computed" holds the aggregated rating
+CREATE TABLE champion_ratings (
+    champion_slug TEXT NOT NULL REFERENCES champions (slug),
+    source        TEXT NOT NULL,
+    weight        INTEGER,
+    location      TEXT NOT NULL,
+    grade         TEXT NOT NULL,
+    PRIMARY KEY (champion_slug, source, location)
+);
+
+CREATE TABLE champion_reviews (
+    champion_slug TEXT NOT NULL REFERENCES champions (slug),
+    location      TEXT NOT NULL,
+    score         REAL NOT NULL,
+    PRIMARY KEY (champion_slug, location)
+);
+
+CREATE TABLE status_effects (
+    slug            TEXT PRIMARY KEY,
+    type            TEXT,
+    effect_type     TEXT,
+    raw_description TEXT,
+    image_slug      TEXT,
+    website_link    TEXT
+);
+
+CREATE TABLE champion_effects (
+    champion_slug TEXT NOT NULL REFERENCES champions (slug),
+    effect_slug   TEXT NOT NULL REFERENCES status_effects (slug),
+    PRIMARY KEY (champion_slug, effect_slug)
+);
+
+CREATE TABLE skills (
+    champion_slug   TEXT NOT NULL REFERENCES champions (slug),
+    slug            TEXT NOT NULL,
+    position        INTEGER NOT NULL,
+    name            TEXT NOT NULL,
+    passive         INTEGER NOT NULL,
+    cooldown        INTEGER,
+    skill_number    TEXT,
+    raw_description TEXT,
+    PRIMARY KEY (champion_slug, slug)
+);
+
+CREATE TABLE skill_damage_based_on (
+    champion_slug TEXT NOT NULL,
+    skill_slug    TEXT NOT NULL,
+    stat          TEXT NOT NULL,
+    PRIMARY KEY (champion_slug, skill_slug, stat),
+    FOREIGN KEY (champion_slug, skill_slug) REFERENCES skills (champion_slug, slug)
+);
+
+CREATE TABLE skill_effects (
+    champion_slug TEXT NOT NULL,
+    skill_slug    TEXT NOT NULL,
+    effect_slug   TEXT NOT NULL REFERENCES status_effects (slug),
+    chance        REAL,
+    value         REAL,
+    turns         INTEGER,
+    target_who    TEXT,
+    FOREIGN KEY (champion_slug, skill_slug) REFERENCES skills (champion_slug, slug)
+);
+
+CREATE TABLE skill_upgrades (
+    champion_slug  TEXT NOT NULL,
+    skill_slug     TEXT NOT NULL,
+    level          TEXT NOT NULL,
+    hits           INTEGER,
+    cooldown       INTEGER,
+    target_who     TEXT,
+    target_targets TEXT,
+    raw_detail     TEXT,
+    FOREIGN KEY (champion_slug, skill_slug) REFERENCES skills (champion_slug, slug)
+);
+
+CREATE TABLE auras (
+    champion_slug   TEXT NOT NULL REFERENCES champions (slug),
+    position        INTEGER NOT NULL,
+    raw_description TEXT,
+    value           INTEGER,
+    percentage      INTEGER NOT NULL,
+    PRIMARY KEY (champion_slug, position)
+);
+
+CREATE TABLE aura_stats (
+    champion_slug TEXT NOT NULL,
+    aura_position INTEGER NOT NULL,
+    stat          TEXT NOT NULL,
+    FOREIGN KEY (champion_slug, aura_position) REFERENCES auras (champion_slug, position)
+);
+
+CREATE TABLE aura_locations (
+    champion_slug TEXT NOT NULL,
+    aura_position INTEGER NOT NULL,
+    location      TEXT NOT NULL,
+    FOREIGN KEY (champion_slug, aura_position) REFERENCES auras (champion_slug, position)
+);
+
+CREATE TABLE builds (
+    id            INTEGER PRIMARY KEY,
+    champion_slug TEXT NOT NULL REFERENCES champions (slug),
+    source        TEXT,
+    author        TEXT
+);
+
+CREATE TABLE build_locations (
+    build_id INTEGER NOT NULL REFERENCES builds (id),
+    location TEXT NOT NULL
+);
+
+CREATE TABLE build_sets (
+    build_id INTEGER NOT NULL REFERENCES builds (id),
+    position INTEGER NOT NULL,
+    name     TEXT NOT NULL
+);
+
+-- kind is "main" or "additional"
+CREATE TABLE build_stats (
+    build_id INTEGER NOT NULL REFERENCES builds (id),
+    piece    TEXT NOT NULL,
+    kind     TEXT NOT NULL,
+    position INTEGER NOT NULL,
+    stat     TEXT NOT NULL
+);
+
+CREATE TABLE masteries (
+    slug        TEXT PRIMARY KEY,
+    name        TEXT NOT NULL,
+    star        INTEGER,
+    tree        INTEGER,
+    level       INTEGER,
+    scroll_type INTEGER,
+    unlock      INTEGER,
+    description TEXT,
+    image_slug  TEXT
+);
+
+CREATE TABLE champion_mastery_sets (
+    id            INTEGER PRIMARY KEY,
+    champion_slug TEXT NOT NULL REFERENCES champions (slug),
+    source        TEXT,
+    author        TEXT
+);
+
+CREATE TABLE champion_mastery_set_locations (
+    set_id   INTEGER NOT NULL REFERENCES champion_mastery_sets (id),
+    location TEXT NOT NULL
+);
+
+-- tree is "offense", "defense" or "support"
+CREATE TABLE champion_mastery_set_masteries (
+    set_id  INTEGER NOT NULL REFERENCES champion_mastery_sets (id),
+    tree    TEXT NOT NULL,
+    mastery TEXT NOT NULL
+);
+
+CREATE TABLE fusions (
+    slug               TEXT PRIMARY KEY,
+    name               TEXT NOT NULL,
+    champion_slug      TEXT NOT NULL REFERENCES champions (slug),
+    parent_fusion_slug TEXT REFERENCES fusions (slug),
+    date_added         TEXT,
+    time_start         TEXT,
+    time_end           TEXT,
+    active             INTEGER NOT NULL,
+    "limit"            INTEGER
+);
+
+CREATE TABLE fusion_ingredients (
+    fusion_slug            TEXT NOT NULL REFERENCES fusions (slug),
+    champion_slug          TEXT NOT NULL REFERENCES champions (slug),
+    level                  INTEGER,
+    stars                  INTEGER,
+    ascended_stars         INTEGER,
+    ingredient_fusion_slug TEXT REFERENCES fusions (slug)
+);
+
+CREATE TABLE champion_fusions (
+    champion_slug TEXT NOT NULL REFERENCES champions (slug),
+    fusion_slug   TEXT NOT NULL REFERENCES fusions (slug),
+    fusion_type   TEXT,
+    PRIMARY KEY (champion_slug, fusion_slug)
+);
+
+CREATE TABLE videos (
+    champion_slug TEXT NOT NULL REFERENCES champions (slug),
+    source        TEXT NOT NULL,
+    id            TEXT NOT NULL,
+    author        TEXT,
+    date_added    TEXT,
+    PRIMARY KEY (champion_slug, source, id)
+);
+
+CREATE INDEX champions_faction ON champions (faction_slug);
+CREATE INDEX champion_ratings_location ON champion_ratings (location, grade);
+CREATE INDEX champion_effects_effect ON champion_effects (effect_slug);
+CREATE INDEX skill_effects_effect ON skill_effects (effect_slug);
+CREATE INDEX fusion_ingredients_champion ON fusion_ingredients (champion_slug);
@@ -0,0 +1,336 @@
+package export
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/raid-codex/tools/common"
+	"github.com/raid-codex/tools/importer"
+	"github.com/xuri/excelize/v2"
+)
+
+// Table is a flat sheet, every row has as many cells as Header. Only the champions table has Paths,
+// the builds and masteries tables are export-only as a champion spans several of their rows.
+type Table struct {
+	Name   string
+	Header []string
+	Rows   [][]string
+	// Paths holds, for each column, the champion path it is imported back to, or "" when it is not importable
+	Paths []string
+}
+
+// Column is a champion value exported in a sheet. Path follows the json tags of Champion the
+// same way importer mappings do, columns with a Path are imported back losslessly. Columns
+// without a Path are export-only: slugs, names of linked entities and values Sanitize computes,
+// like the overall rating and the effects of a champion.
+type Column struct {
+	Header string
+	Path   string
+	Value  func(*common.Champion) string
+}
+
+const (
+	ColumnGroup_Identity  = "identity"
+	ColumnGroup_Faction   = "faction"
+	ColumnGroup_Stats     = "stats"
+	ColumnGroup_Ratings   = "ratings"
+	ColumnGroup_Cooldowns = "cooldowns"
+	ColumnGroup_Effects   = "effects"
+)
+
+// statsLevel is the level of the characteristics exported in the stats group
+const statsLevel = 60
+
+// maxSkills is the number of skill columns of the cooldowns group
+const maxSkills = 5
+
+var (
+	// ColumnGroups lists the groups that can be selected, in the order they are exported
+	ColumnGroups = []string{ColumnGroup_Identity, ColumnGroup_Faction, ColumnGroup_Stats, ColumnGroup_Ratings, ColumnGroup_Cooldowns, ColumnGroup_Effects}
+
+	nameColumn = Column{Header: "name", Path: "name", Value: func(c *common.Champion) string { return c.Name }}
+)
+
+// Columns returns the columns of a group
+func Columns(group string) ([]Column, error) {
+	switch group {
+	case ColumnGroup_Identity:
+		return []Column{
+			{Header: "slug", Value: func(c *common.Champion) string { return c.Slug }},
+			{Header: "rarity", Path: "rarity", Value: func(c *common.Champion) string { return c.Rarity }},
+			{Header: "element", Path: "element", Value: func(c *common.Champion) string { return c.Element }},
+			{Header: "type", Path: "type", Value: func(c *common.Champion) string { return c.Type }},
+		}, nil
+	case ColumnGroup_Faction:
+		return []Column{
+			{Header: "faction_slug", Path: "faction_slug", Value: func(c *common.Champion) string { return c.FactionSlug }},
+			{Header: "faction", Value: func(c *common.Champion) string { return c.Faction.Name }},
+		}, nil
+	case ColumnGroup_Stats:
+		return statsColumns(), nil
+	case ColumnGroup_Ratings:
+		return ratingColumns(), nil
+	case ColumnGroup_Cooldowns:
+		return cooldownColumns(), nil
+	case ColumnGroup_Effects:
+		return []Column{
+			// computed from the effects of skills
+			{Header: "effect_slugs", Value: func(c *common.Champion) string {
+				return strings.Join(c.EffectSlugs, importer.ListSeparator)
+			}},
+		}, nil
+	}
+	return nil, errors.NotFoundf("column group %s", group)
+}
+
+func statsColumns() []Column {
+	columns := make([]Column, 0)
+	for _, field := range jsonFields(&common.Characteristics{}) {
+		tag := field.tag
+		columns = append(columns, Column{
+			Header: tag,
+			Path:   fmt.Sprintf("characteristics.%d.%s", statsLevel, tag),
+			Value: func(c *common.Champion) string {
+				stats, ok := c.Characteristics[statsLevel]
+				if !ok {
+					return ""
+				}
+				for _, field := range jsonFields(&stats) {
+					if field.tag == tag {
+						return formatValue(field.value.Interface())
+					}
+				}
+				return ""
+			},
+		})
+	}
+	return columns
+}
+
+// ratingColumns exports the rating computed from the ratings of every source
+func ratingColumns() []Column {
+	columns := make([]Column, 0)
+	for _, field := range jsonFields(&common.Rating{}) {
+		tag := field.tag
+		columns = append(columns, Column{
+			Header: fmt.Sprintf("rating_%s", tag),
+			Value: func(c *common.Champion) string {
+				if c.Rating == nil {
+					return ""
+				}
+				for _, field := range jsonFields(c.Rating) {
+					if field.tag == tag {
+						return field.value.String()
+					}
+				}
+				return ""
+			},
+		})
+	}
+	return columns
+}
+
+func cooldownColumns() []Column {
+	columns := make([]Column, 0)
+	for i := 0; i < maxSkills; i++ {
+		idx := i
+		columns = append(columns,
+			Column{
+				Header: fmt.Sprintf("skill_%d", idx+1),
+				Value: func(c *common.Champion) string {
+					if idx >= len(c.Skills) {
+						return ""
+					}
+					return c.Skills[idx].Name
+				},
+			},
+			Column{
+				Header: fmt.Sprintf("skill_%d_cooldown", idx+1),
+				Path:   fmt.Sprintf("skills.%d.cooldown", idx),
+				Value: func(c *common.Champion) string {
+					if idx >= len(c.Skills) {
+						return ""
+					}
+					return formatValue(c.Skills[idx].Cooldown)
+				},
+			},
+		)
+	}
+	return columns
+}
+
+// ChampionsTable flattens champions with the columns of groups, the name always comes first
+func ChampionsTable(champions common.ChampionList, groups []string) (*Table, error) {
+	columns := []Column{nameColumn}
+	for _, group := range groups {
+		groupColumns, errColumns := Columns(group)
+		if errColumns != nil {
+			return nil, errColumns
+		}
+		columns = append(columns, groupColumns...)
+	}
+	table := &Table{Name: "champions"}
+	for _, column := range columns {
+		table.Header = append(table.Header, column.Header)
+		table.Paths = append(table.Paths, column.Path)
+	}
+	for _, champion := range champions {
+		row := make([]string, len(columns))
+		for idx, column := range columns {
+			row[idx] = column.Value(champion)
+		}
+		table.Rows = append(table.Rows, row)
+	}
+	return table, nil
+}
+
+// BuildsTable lists the recommended builds of champions, one line per build. It is export-only.
+func BuildsTable(champions common.ChampionList) *Table {
+	pieces := jsonFields(&common.StatsPriority{})
+	table := &Table{Name: "builds", Header: []string{"name", "from", "author", "locations", "sets"}}
+	for _, piece := range pieces {
+		table.Header = append(table.Header, fmt.Sprintf("%s_main", piece.tag), fmt.Sprintf("%s_additional", piece.tag))
+	}
+	for _, champion := range champions {
+		for _, build := range champion.RecommendedBuilds {
+			row := []string{champion.Name, build.From, build.Author, joinList(build.Locations), joinList(build.Sets)}
+			for _, piece := range pieces {
+				var main, additional []string
+				if build.Stats != nil {
+					if priority := getStatPriority(build.Stats, piece.tag); priority != nil {
+						main, additional = priority.MainStats, priority.AdditionalStats
+						if len(main) == 0 && priority.MainStat != "" {
+							main = []string{priority.MainStat}
+						}
+					}
+				}
+				row = append(row, joinList(main), joinList(additional))
+			}
+			table.Rows = append(table.Rows, row)
+		}
+	}
+	return table
+}
+
+func getStatPriority(stats *common.StatsPriority, piece string) *common.StatPriority {
+	for _, field := range jsonFields(stats) {
+		if field.tag == piece {
+			return field.value.Interface().(*common.StatPriority)
+		}
+	}
+	return nil
+}
+
+// MasteriesTable lists the recommended masteries of champions, one line per set of masteries. It is
+// export-only.
+func MasteriesTable(champions common.ChampionList) *Table {
+	table := &Table{Name: "masteries", Header: []string{"name", "from", "author", "locations", "offense", "defense", "support"}}
+	for _, champion := range champions {
+		for _, m := range champion.Masteries {
+			table.Rows = append(table.Rows, []string{
+				champion.Name, m.From, m.Author, joinList(m.Locations), joinList(m.Offense), joinList(m.Defense), joinList(m.Support),
+			})
+		}
+	}
+	return table
+}
+
+// Mapping returns the importer mapping reading the table back, only columns with a path are imported
+func (t *Table) Mapping(source string) (*importer.Mapping, error) {
+	mapping := &importer.Mapping{
+		Name:      fmt.Sprintf("export-%s", t.Name),
+		Source:    source,
+		Delimiter: ",",
+		Header:    strings.Join(t.Header, ","),
+		Columns:   len(t.Header),
+		Fields:    make([]*importer.Field, 0),
+	}
+	for idx, path := range t.Paths {
+		if path == "" || path == nameColumn.Path {
+			continue
+		}
+		// empty cells are values the champion does not have, they must not reset anything
+		field := &importer.Field{Column: idx, Path: path, Skip: []string{""}}
+		if strings.HasPrefix(path, "skills.") {
+			// skills are merged as a whole since a line may only update some of them
+			field.Provenance = "skills"
+		}
+		mapping.Fields = append(mapping.Fields, field)
+	}
+	return mapping, mapping.Validate()
+}
+
+// WriteCSV writes the header and rows of the table
+func (t *Table) WriteCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	if errHeader := writer.Write(t.Header); errHeader != nil {
+		return errHeader
+	}
+	if errRows := writer.WriteAll(t.Rows); errRows != nil {
+		return errRows
+	}
+	return writer.Error()
+}
+
+// WriteXLSX writes every table as a sheet of a workbook saved at filename
+func WriteXLSX(filename string, tables ...*Table) error {
+	file := excelize.NewFile()
+	defer file.Close()
+	for idx, table := range tables {
+		if idx == 0 {
+			if errRename := file.SetSheetName(file.GetSheetName(0), table.Name); errRename != nil {
+				return errRename
+			}
+		} else if _, errSheet := file.NewSheet(table.Name); errSheet != nil {
+			return errSheet
+		}
+		stream, errStream := file.NewStreamWriter(table.Name)
+		if errStream != nil {
+			return errStream
+		}
+		for rowIdx, row := range append([][]string{table.Header}, table.Rows...) {
+			cells := make([]interface{}, len(row))
+			for cellIdx, cell := range row {
+				cells[cellIdx] = cell
+			}
+			cellName, errCell := excelize.CoordinatesToCellName(1, rowIdx+1)
+			if errCell != nil {
+				return errCell
+			}
+			if errRow := stream.SetRow(cellName, cells); errRow != nil {
+				return errors.Annotatef(errRow, "sheet %s", table.Name)
+			}
+		}
+		if errFlush := stream.Flush(); errFlush != nil {
+			return errFlush
+		}
+	}
+	return file.SaveAs(filename)
+}
+
+// SortedChampions returns champions ordered by name, so exports diff nicely
+func SortedChampions(champions common.ChampionList) common.ChampionList {
+	sorted := make(common.ChampionList, len(champions))
+	copy(sorted, champions)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Name < sorted[j].Name })
+	return sorted
+}
+
+func joinList(values []string) string {
+	return strings.Join(values, importer.ListSeparator)
+}
+
+func formatValue(v interface{}) string {
+	switch value := v.(type) {
+	case int64:
+		return strconv.FormatInt(value, 10)
+	case float64:
+		return strconv.FormatFloat(value, 'f', -1, 64)
+	}
+	return fmt.Sprintf("%v", v)
+}
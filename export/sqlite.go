@@ -0,0 +1,431 @@
+package export
+
+import (
+	"database/sql"
+	_ "embed"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/raid-codex/tools/common"
+	"github.com/raid-codex/tools/utils"
+	_ "modernc.org/sqlite"
+)
+
+// SQLiteSchemaVersion is stored as the user_version of exported databases, it changes
+// whenever schema/sqlite_v<version>.sql would break existing queries
+const SQLiteSchemaVersion = 1
+
+//go:embed schema/sqlite_v1.sql
+var sqliteSchema string
+
+// SQLiteSchema returns the statements creating the tables of the current schema version
+func SQLiteSchema() string {
+	return sqliteSchema
+}
+
+// SQLite writes the data loaded in the factory to a new database at filename, replacing any existing file
+func SQLite(filename string) error {
+	if errRemove := os.Remove(filename); errRemove != nil && !os.IsNotExist(errRemove) {
+		return errRemove
+	}
+	db, errOpen := sql.Open("sqlite", filename)
+	if errOpen != nil {
+		return errOpen
+	}
+	defer db.Close()
+	if _, errSchema := db.Exec(sqliteSchema); errSchema != nil {
+		return errors.Annotate(errSchema, "cannot create schema")
+	}
+	tx, errTx := db.Begin()
+	if errTx != nil {
+		return errTx
+	}
+	w := &sqliteWriter{tx: tx}
+	if errWrite := w.writeAll(); errWrite != nil {
+		tx.Rollback()
+		return errWrite
+	}
+	if errCommit := tx.Commit(); errCommit != nil {
+		return errCommit
+	}
+	_, errVersion := db.Exec(fmt.Sprintf("PRAGMA user_version = %d", SQLiteSchemaVersion))
+	return errVersion
+}
+
+type sqliteWriter struct {
+	tx *sql.Tx
+}
+
+func (w *sqliteWriter) insert(table string, values ...interface{}) (int64, error) {
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(values)), ", ")
+	res, err := w.tx.Exec(fmt.Sprintf("INSERT INTO %s VALUES (%s)", table, placeholders), values...)
+	if err != nil {
+		return 0, errors.Annotatef(err, "cannot insert into %s", table)
+	}
+	return res.LastInsertId()
+}
+
+func (w *sqliteWriter) writeAll() error {
+	steps := []struct {
+		name  string
+		write func() error
+	}{
+		{"schema info", w.writeSchemaInfo},
+		{"factions", w.writeFactions},
+		{"status effects", w.writeStatusEffects},
+		{"masteries", w.writeMasteries},
+		{"champions", w.writeChampions},
+		{"fusions", w.writeFusions},
+	}
+	for _, step := range steps {
+		if err := step.write(); err != nil {
+			return errors.Annotatef(err, "cannot export %s", step.name)
+		}
+	}
+	return nil
+}
+
+func (w *sqliteWriter) writeSchemaInfo() error {
+	info := [][2]string{
+		{"schema_version", fmt.Sprintf("%d", SQLiteSchemaVersion)},
+		{"generated_at", time.Now().UTC().Format(time.RFC3339)},
+	}
+	for _, kv := range info {
+		if _, err := w.insert("schema_info", kv[0], kv[1]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (w *sqliteWriter) writeFactions() error {
+	factions, errFactions := common.GetFactions()
+	if errFactions != nil {
+		return errFactions
+	}
+	for _, f := range factions {
+		_, err := w.insert("factions", f.Slug, f.Name, nullString(f.DateAdded), f.WebsiteLink, f.ImageSlug, f.NumberOfChampions, nullString(f.RawDescription))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (w *sqliteWriter) writeStatusEffects() error {
+	effects, errEffects := common.GetStatuseffects()
+	if errEffects != nil {
+		return errEffects
+	}
+	for _, se := range effects {
+		_, err := w.insert("status_effects", se.Slug, se.Type, se.EffectType, nullString(se.RawDescription), se.ImageSlug, se.WebsiteLink)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (w *sqliteWriter) writeMasteries() error {
+	masteries, errMasteries := common.GetMasteries()
+	if errMasteries != nil {
+		return errMasteries
+	}
+	for _, m := range masteries {
+		_, err := w.insert("masteries", m.Slug, m.Name, m.Star, m.Tree, m.Level, m.ScrollType, int64(m.Unlock), nullString(m.Description), m.ImageSlug)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (w *sqliteWriter) writeChampions() error {
+	champions, errChampions := common.GetChampions()
+	if errChampions != nil {
+		return errChampions
+	}
+	for _, c := range champions {
+		if err := w.writeChampion(c); err != nil {
+			return errors.Annotatef(err, "champion %s", c.Slug)
+		}
+	}
+	return nil
+}
+
+func (w *sqliteWriter) writeChampion(c *common.Champion) error {
+	var overall interface{}
+	if c.Rating != nil {
+		overall = nullString(c.Rating.Overall)
+	}
+	var reviewCount interface{}
+	if c.Reviews != nil {
+		reviewCount = c.Reviews.NumberOfReviews
+	}
+	_, err := w.insert("champions", c.Slug, c.Name, nullString(c.FactionSlug), c.Rarity, c.Element, c.Type,
+		nullString(c.DateAdded), c.WebsiteLink, c.ImageSlug, nullString(c.Thumbnail), nullString(c.Lore), overall, reviewCount)
+	if err != nil {
+		return err
+	}
+	for level, ch := range c.Characteristics {
+		_, err := w.insert("champion_characteristics", c.Slug, level, ch.HP, ch.Attack, ch.Defense, ch.Speed,
+			ch.CriticalRate, ch.CriticalDamage, ch.Resistance, ch.Accuracy)
+		if err != nil {
+			return err
+		}
+	}
+	for _, tag := range utils.UniqueSlice(c.Tags) {
+		if _, err := w.insert("champion_tags", c.Slug, tag); err != nil {
+			return err
+		}
+	}
+	if errRatings := w.writeRatings(c); errRatings != nil {
+		return errRatings
+	}
+	if c.Reviews != nil {
+		for _, field := range jsonFields(c.Reviews) {
+			if field.tag == "amount" || field.value.Float() == 0 {
+				continue
+			}
+			if _, err := w.insert("champion_reviews", c.Slug, field.tag, field.value.Float()); err != nil {
+				return err
+			}
+		}
+	}
+	for _, effect := range utils.UniqueSlice(c.EffectSlugs) {
+		if _, err := w.insert("champion_effects", c.Slug, effect); err != nil {
+			return err
+		}
+	}
+	for idx, skill := range c.Skills {
+		if err := w.writeSkill(c, idx, skill); err != nil {
+			return errors.Annotatef(err, "skill %s", skill.Name)
+		}
+	}
+	for idx, aura := range c.Auras {
+		_, err := w.insert("auras", c.Slug, idx, nullString(aura.RawDescription), aura.Value, aura.Percentage)
+		if err != nil {
+			return err
+		}
+		for _, stat := range aura.Stats {
+			if _, err := w.insert("aura_stats", c.Slug, idx, stat); err != nil {
+				return err
+			}
+		}
+		for _, location := range aura.Locations {
+			if _, err := w.insert("aura_locations", c.Slug, idx, location); err != nil {
+				return err
+			}
+		}
+	}
+	for _, build := range c.RecommendedBuilds {
+		if err := w.writeBuild(c, build); err != nil {
+			return err
+		}
+	}
+	for _, masteries := range c.Masteries {
+		if err := w.writeChampionMasteries(c, masteries); err != nil {
+			return err
+		}
+	}
+	for _, fusion := range c.FusionData {
+		if _, err := w.insert("champion_fusions", c.Slug, fusion.FusionSlug, nullString(fusion.FusionType)); err != nil {
+			return err
+		}
+	}
+	for _, video := range c.Videos {
+		if _, err := w.insert("videos", c.Slug, video.Source, video.ID, nullString(video.Author), nullString(video.DateAdded)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeRatings stores the computed rating of the champion under the "computed" source, then every rating source
+func (w *sqliteWriter) writeRatings(c *common.Champion) error {
+	sources := make([]*common.RatingSource, 0, len(c.AllRatings)+1)
+	if c.Rating != nil {
+		sources = append(sources, &common.RatingSource{Source: "computed", Rating: c.Rating})
+	}
+	sources = append(sources, c.AllRatings...)
+	for _, source := range sources {
+		if source.Rating == nil {
+			continue
+		}
+		var weight interface{}
+		if source.Source != "computed" {
+			weight = source.Weight
+		}
+		for _, field := range jsonFields(source.Rating) {
+			grade := field.value.String()
+			if grade == "" {
+				continue
+			}
+			if _, err := w.insert("champion_ratings", c.Slug, source.Source, weight, field.tag, grade); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (w *sqliteWriter) writeSkill(c *common.Champion, position int, s *common.Skill) error {
+	_, err := w.insert("skills", c.Slug, s.Slug, position, s.Name, s.Passive, s.Cooldown, nullString(s.SkillNumber), nullString(s.RawDescription))
+	if err != nil {
+		return err
+	}
+	for _, stat := range utils.UniqueSlice(s.DamageBasedOn) {
+		if _, err := w.insert("skill_damage_based_on", c.Slug, s.Slug, stat); err != nil {
+			return err
+		}
+	}
+	for _, effect := range s.Effects {
+		var who interface{}
+		if effect.Target != nil {
+			who = nullString(effect.Target.Who)
+		}
+		_, err := w.insert("skill_effects", c.Slug, s.Slug, effect.Slug, effect.Chance, effect.Value, effect.Turns, who)
+		if err != nil {
+			return err
+		}
+	}
+	for _, upgrade := range s.Upgrades {
+		var who, targets interface{}
+		if upgrade.Target != nil {
+			who, targets = nullString(upgrade.Target.Who), nullString(upgrade.Target.Targets)
+		}
+		_, err := w.insert("skill_upgrades", c.Slug, s.Slug, upgrade.Level, upgrade.Hits, upgrade.Cooldown, who, targets, nullString(upgrade.RawDetail))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (w *sqliteWriter) writeBuild(c *common.Champion, b *common.Build) error {
+	id, err := w.insert("builds", nil, c.Slug, nullString(b.From), nullString(b.Author))
+	if err != nil {
+		return err
+	}
+	for _, location := range b.Locations {
+		if _, err := w.insert("build_locations", id, location); err != nil {
+			return err
+		}
+	}
+	for idx, set := range b.Sets {
+		if _, err := w.insert("build_sets", id, idx, set); err != nil {
+			return err
+		}
+	}
+	if b.Stats == nil {
+		return nil
+	}
+	for _, piece := range jsonFields(b.Stats) {
+		if piece.value.IsNil() {
+			continue
+		}
+		priority := piece.value.Interface().(*common.StatPriority)
+		mainStats := priority.MainStats
+		if len(mainStats) == 0 && priority.MainStat != "" {
+			mainStats = []string{priority.MainStat}
+		}
+		for kind, stats := range map[string][]string{"main": mainStats, "additional": priority.AdditionalStats} {
+			for idx, stat := range stats {
+				if _, err := w.insert("build_stats", id, piece.tag, kind, idx, stat); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func (w *sqliteWriter) writeChampionMasteries(c *common.Champion, m *common.ChampionMasteries) error {
+	id, err := w.insert("champion_mastery_sets", nil, c.Slug, nullString(m.From), nullString(m.Author))
+	if err != nil {
+		return err
+	}
+	for _, location := range m.Locations {
+		if _, err := w.insert("champion_mastery_set_locations", id, location); err != nil {
+			return err
+		}
+	}
+	for tree, masteries := range map[string][]string{"offense": m.Offense, "defense": m.Defense, "support": m.Support} {
+		for _, mastery := range masteries {
+			if _, err := w.insert("champion_mastery_set_masteries", id, tree, mastery); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (w *sqliteWriter) writeFusions() error {
+	fusions, errFusions := common.GetFusions()
+	if errFusions != nil {
+		return errFusions
+	}
+	// parents are referenced by slug, insert every fusion before the ingredients
+	for _, f := range fusions {
+		var parent, limit interface{}
+		if f.ParentFusionSlug != nil {
+			parent = *f.ParentFusionSlug
+		}
+		if f.Limit != nil {
+			limit = *f.Limit
+		}
+		_, err := w.insert("fusions", f.Slug, f.Name, f.ChampionSlug, parent, nullString(f.DateAdded),
+			nullTime(f.TimeStart), nullTime(f.TimeEnd), f.Active, limit)
+		if err != nil {
+			return errors.Annotatef(err, "fusion %s", f.Slug)
+		}
+	}
+	for _, f := range fusions {
+		for _, ingredient := range f.Ingredients {
+			var fusionSlug interface{}
+			if ingredient.FusionSlug != nil {
+				fusionSlug = *ingredient.FusionSlug
+			}
+			_, err := w.insert("fusion_ingredients", f.Slug, ingredient.ChampionSlug, ingredient.Level,
+				ingredient.Stars, ingredient.AscendedStars, fusionSlug)
+			if err != nil {
+				return errors.Annotatef(err, "fusion %s", f.Slug)
+			}
+		}
+	}
+	return nil
+}
+
+type jsonField struct {
+	tag   string
+	value reflect.Value
+}
+
+// jsonFields lists the fields of the struct pointed by v with their json name, in declaration order
+func jsonFields(v interface{}) []jsonField {
+	indV := reflect.Indirect(reflect.ValueOf(v))
+	fields := make([]jsonField, 0, indV.NumField())
+	for i := 0; i < indV.NumField(); i++ {
+		tag := strings.Split(indV.Type().Field(i).Tag.Get("json"), ",")[0]
+		fields = append(fields, jsonField{tag: tag, value: indV.Field(i)})
+	}
+	return fields
+}
+
+func nullString(str string) interface{} {
+	if str == "" {
+		return nil
+	}
+	return str
+}
+
+func nullTime(t *time.Time) interface{} {
+	if t == nil {
+		return nil
+	}
+	return t.UTC().Format(time.RFC3339)
+}
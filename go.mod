@@ -1,40 +1,71 @@
 module github.com/raid-codex/tools
 
-go 1.13
+go 1.21
 
 require (
 	github.com/PuerkitoBio/goquery v1.5.1-0.20190109230704-3dcf72e6c17f
+	github.com/cloudflare/cloudflare-go v0.14.0
+	github.com/davecgh/go-spew v1.1.1
+	github.com/gin-gonic/gin v1.4.1-0.20190924141841-9b9f4fab34cc
+	github.com/go-test/deep v1.0.4-0.20190818181632-597fd8504439
+	github.com/juju/errors v0.0.0-20170703010042-c7d06af17c68
+	github.com/sirupsen/logrus v1.4.2
+	github.com/sogko/go-wordpress v0.0.0-20160322054548-0f4f3dc4231f
+	github.com/tdewolff/minify v2.3.6+incompatible
+	github.com/xeipuuv/gojsonschema v1.1.1-0.20190423132807-354ad34c2300
+	github.com/xuri/excelize/v2 v2.8.1
+	gopkg.in/alecthomas/kingpin.v2 v2.2.6
+	modernc.org/sqlite v1.29.10
+)
+
+require (
 	github.com/alecthomas/template v0.0.0-20160405071501-a0175ee3bccc // indirect
 	github.com/alecthomas/units v0.0.0-20151022065526-2efee857e7cf // indirect
 	github.com/andybalholm/cascadia v1.0.1-0.20181012154424-680b6a57bda4 // indirect
-	github.com/cloudflare/cloudflare-go v0.14.0
-	github.com/davecgh/go-spew v1.1.1
+	github.com/dustin/go-humanize v1.0.1 // indirect
 	github.com/elazarl/goproxy v0.0.0-20200315184450-1f3cb6622dad // indirect
 	github.com/gin-contrib/sse v0.1.1-0.20190905051334-43f0f29dbd2b // indirect
-	github.com/gin-gonic/gin v1.4.1-0.20190924141841-9b9f4fab34cc
 	github.com/go-playground/locales v0.12.2-0.20190430153329-630ebbb60284 // indirect
 	github.com/go-playground/universal-translator v0.16.1-0.20170327191703-71201497bace // indirect
-	github.com/go-test/deep v1.0.4-0.20190818181632-597fd8504439
 	github.com/golang/protobuf v1.3.3-0.20190920234318-1680a479a2cf // indirect
-	github.com/juju/errors v0.0.0-20170703010042-c7d06af17c68
+	github.com/google/uuid v1.6.0 // indirect
+	github.com/hashicorp/golang-lru/v2 v2.0.7 // indirect
+	github.com/json-iterator/go v1.1.7 // indirect
 	github.com/juju/loggo v0.0.0-20190526231331-6e530bcce5d8 // indirect
 	github.com/juju/testing v0.0.0-20191001232224-ce9dec17d28b // indirect
+	github.com/konsorten/go-windows-terminal-sequences v1.0.1 // indirect
 	github.com/leodido/go-urn v1.1.1-0.20181204092800-a67a23e1c1af // indirect
-	github.com/mattn/go-isatty v0.0.10-0.20190818123653-bf9a1dea1961 // indirect
+	github.com/mattn/go-isatty v0.0.20 // indirect
+	github.com/modern-go/concurrent v0.0.0-20180228061459-e0a39a4cb421 // indirect
+	github.com/modern-go/reflect2 v0.0.0-20180701023420-4b7aa43c6742 // indirect
+	github.com/mohae/deepcopy v0.0.0-20170929034955-c48cc78d4826 // indirect
 	github.com/moul/http2curl v0.0.0-20161031194548-4e24498b31db // indirect
+	github.com/ncruces/go-strftime v0.1.9 // indirect
 	github.com/parnurzeal/gorequest v0.2.16-0.20170429061244-5bf13be19878 // indirect
-	github.com/sirupsen/logrus v1.4.2
+	github.com/pkg/errors v0.9.1 // indirect
+	github.com/remyoudompheng/bigfft v0.0.0-20230129092748-24d4a6f8daec // indirect
+	github.com/richardlehane/mscfb v1.0.4 // indirect
+	github.com/richardlehane/msoleps v1.0.3 // indirect
 	github.com/smartystreets/goconvey v1.6.4 // indirect
-	github.com/sogko/go-wordpress v0.0.0-20160322054548-0f4f3dc4231f
-	github.com/tdewolff/minify v2.3.6+incompatible
 	github.com/tdewolff/parse v2.3.4+incompatible // indirect
 	github.com/tdewolff/test v1.0.6 // indirect
+	github.com/ugorji/go/codec v1.1.7 // indirect
 	github.com/xeipuuv/gojsonpointer v0.0.0-20180127040702-4e3ac2762d5f // indirect
 	github.com/xeipuuv/gojsonreference v0.0.0-20180127040603-bd5ef7bd5415 // indirect
-	github.com/xeipuuv/gojsonschema v1.1.1-0.20190423132807-354ad34c2300
-	golang.org/x/net v0.0.0-20210326220855-61e056675ecf // indirect
-	golang.org/x/text v0.3.5 // indirect
+	github.com/xuri/efp v0.0.0-20231025114914-d1ff6096ae53 // indirect
+	github.com/xuri/nfp v0.0.0-20230919160717-d98342af3f05 // indirect
+	golang.org/x/crypto v0.21.0 // indirect
+	golang.org/x/net v0.22.0 // indirect
+	golang.org/x/sys v0.19.0 // indirect
+	golang.org/x/text v0.14.0 // indirect
 	golang.org/x/time v0.0.0-20210220033141-f8bda1e9f3ba // indirect
-	gopkg.in/alecthomas/kingpin.v2 v2.2.6
+	gopkg.in/go-playground/validator.v9 v9.29.1 // indirect
 	gopkg.in/mgo.v2 v2.0.0-20190816093944-a6b53ec6cb22 // indirect
+	gopkg.in/yaml.v2 v2.2.3 // indirect
+	modernc.org/gc/v3 v3.0.0-20240107210532-573471604cb6 // indirect
+	modernc.org/libc v1.49.3 // indirect
+	modernc.org/mathutil v1.6.0 // indirect
+	modernc.org/memory v1.8.0 // indirect
+	modernc.org/strutil v1.2.0 // indirect
+	modernc.org/token v1.1.0 // indirect
 )
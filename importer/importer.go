@@ -0,0 +1,268 @@
+package importer
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/raid-codex/tools/common"
+	"github.com/raid-codex/tools/utils"
+)
+
+// Importer applies the lines of a sheet to champion files following a Mapping
+type Importer struct {
+	Mapping *Mapping
+	// OutputDirectory receives the updated champion files
+	OutputDirectory string
+	// Merger decides which values may be replaced, every value is accepted when nil
+	Merger *common.Merger
+	// Sanitize champions before they are saved, this needs the factory to be initialized
+	Sanitize bool
+
+	champions map[string]*common.Champion
+}
+
+// Result lists the champions updated by an import and the names that matched no champion
+type Result struct {
+	Updated []*common.Champion
+	Unknown []*common.ChampionNotFoundError
+}
+
+// ErrUnknown returns an error naming the champions that were not found, if any
+func (r *Result) ErrUnknown() error {
+	if len(r.Unknown) == 0 {
+		return nil
+	}
+	messages := make([]string, len(r.Unknown))
+	for idx, unknown := range r.Unknown {
+		messages[idx] = unknown.Error()
+	}
+	return fmt.Errorf("%d names matched no champion:\n%s", len(r.Unknown), strings.Join(messages, "\n"))
+}
+
+// New loads the champions of championsDirectory, which may be empty to start from no champion
+func New(mapping *Mapping, championsDirectory, outputDirectory string) (*Importer, error) {
+	importer := &Importer{
+		Mapping:         mapping,
+		OutputDirectory: outputDirectory,
+		champions:       map[string]*common.Champion{},
+	}
+	if championsDirectory == "" {
+		return importer, nil
+	}
+	dir, errDir := ioutil.ReadDir(championsDirectory)
+	if errDir != nil {
+		return nil, errDir
+	}
+	for _, file := range dir {
+		if file.Name() == "index.json" || !strings.HasSuffix(file.Name(), ".json") {
+			continue
+		}
+		champion, errChampion := readChampion(filepath.Join(championsDirectory, file.Name()))
+		if errChampion != nil {
+			return nil, errors.Annotatef(errChampion, "cannot read %s", file.Name())
+		}
+		importer.champions[champion.Name] = champion
+	}
+	return importer, nil
+}
+
+func readChampion(filename string) (*common.Champion, error) {
+	file, errOpen := os.Open(filename)
+	if errOpen != nil {
+		return nil, errOpen
+	}
+	defer file.Close()
+	var champion common.Champion
+	errJSON := json.NewDecoder(file).Decode(&champion)
+	if errJSON != nil {
+		return nil, errJSON
+	}
+	return &champion, nil
+}
+
+// ReadFile reads a sheet exported as CSV with the delimiter of the mapping
+func (i *Importer) ReadFile(filename string) ([][]string, error) {
+	file, errFile := os.Open(filename)
+	if errFile != nil {
+		return nil, errFile
+	}
+	defer file.Close()
+	reader := csv.NewReader(file)
+	reader.Comma = []rune(i.Mapping.Delimiter)[0]
+	reader.FieldsPerRecord = -1
+	return reader.ReadAll()
+}
+
+// ImportFile reads filename and imports its lines
+func (i *Importer) ImportFile(filename string) (*Result, error) {
+	content, errRead := i.ReadFile(filename)
+	if errRead != nil {
+		return nil, errRead
+	}
+	return i.Import(content)
+}
+
+func (i *Importer) Import(content [][]string) (*Result, error) {
+	m := i.Mapping
+	result := &Result{Updated: make([]*common.Champion, 0), Unknown: make([]*common.ChampionNotFoundError, 0)}
+	updated := map[*common.Champion]bool{}
+	// previous is the last line that is not a header, values are filled down from it
+	var previous []string
+	for idx, line := range content {
+		joined := strings.Join(line, m.Delimiter)
+		if idx == 0 || m.HeaderAnywhere {
+			if errHeader := m.CheckHeader(line); errHeader != nil {
+				return nil, errHeader
+			}
+		}
+		if m.Header != "" && joined == m.Header {
+			continue
+		}
+		if m.Columns > 0 && len(line) != m.Columns {
+			return nil, fmt.Errorf("line %d has %d columns, not %d: %s", idx+1, len(line), m.Columns, joined)
+		} else if m.Key >= len(line) {
+			return nil, fmt.Errorf("line %d has no column %d: %s", idx+1, m.Key, joined)
+		}
+		for _, column := range m.FillDown {
+			if previous != nil && column < len(line) && line[column] == "" && column < len(previous) {
+				line[column] = previous[column]
+			}
+		}
+		previous = line
+		// sheets flag some names, e.g. "Kael!"
+		name := strings.Trim(line[m.Key], " !")
+		if name == "" {
+			continue
+		}
+		champion, errChampion := i.getChampion(name)
+		if notFound, ok := errChampion.(*common.ChampionNotFoundError); ok {
+			result.Unknown = append(result.Unknown, notFound)
+			continue
+		} else if errChampion != nil {
+			return nil, errors.Annotatef(errChampion, "line %d", idx+1)
+		}
+		errApply := i.apply(champion, line)
+		if errApply != nil {
+			return nil, errors.Annotatef(errApply, "line %d (%s)", idx+1, name)
+		}
+		if m.SkillDetail != nil {
+			if errDetail := i.applySkillDetail(champion, line); errDetail != nil {
+				return nil, errors.Annotatef(errDetail, "line %d (%s)", idx+1, name)
+			}
+		}
+		if !updated[champion] {
+			updated[champion] = true
+			result.Updated = append(result.Updated, champion)
+		}
+	}
+	return result, nil
+}
+
+func (i *Importer) getChampion(name string) (*common.Champion, error) {
+	names := make([]string, 0, len(i.champions))
+	for championName := range i.champions {
+		names = append(names, championName)
+	}
+	if match, ok := common.MatchName(name, names); ok {
+		return i.champions[match], nil
+	} else if !i.Mapping.CreateMissing {
+		return nil, common.NewChampionNotFoundError(name, names)
+	}
+	nameOk, errName := common.GetSanitizedName(common.ResolveChampionName(name))
+	if errName != nil {
+		return nil, errName
+	}
+	champion := &common.Champion{Name: nameOk}
+	i.champions[nameOk] = champion
+	return champion, nil
+}
+
+// apply stores the fields of line on a copy of champion first, then every provenance field that
+// changed is merged back to champion as a whole
+func (i *Importer) apply(champion *common.Champion, line []string) error {
+	m := i.Mapping
+	scratch, errCopy := copyChampion(champion)
+	if errCopy != nil {
+		return errCopy
+	}
+	provenanceFields := make([]string, 0)
+	for _, field := range m.Fields {
+		value := line[field.Column]
+		for _, name := range field.Transforms {
+			var errTransform error
+			value, errTransform = transforms[name](value)
+			if errTransform != nil {
+				return errors.Annotatef(errTransform, "column %d", field.Column)
+			}
+		}
+		if utils.InSlice(field.Skip, value) {
+			continue
+		}
+		switch field.Action {
+		case Action_RawSkill:
+			if errSkill := champion.MergeRawSkill(i.Merger, value, m.Source, m.URL); errSkill != nil {
+				return errors.Annotatef(errSkill, "column %d", field.Column)
+			}
+			continue
+		case Action_Aura:
+			scratch.SetAura(value)
+		default:
+			errSet := setPath(reflect.ValueOf(scratch).Elem(), strings.Split(field.Path, "."), func(v reflect.Value) error {
+				return setString(v, value)
+			})
+			if errSet != nil {
+				return errors.Annotatef(errSet, "column %d", field.Column)
+			}
+		}
+		if !utils.InSlice(provenanceFields, field.Provenance) {
+			provenanceFields = append(provenanceFields, field.Provenance)
+		}
+	}
+	for _, field := range provenanceFields {
+		path := strings.Split(field, ".")
+		current := getPath(reflect.ValueOf(champion).Elem(), path)
+		incoming := getPath(reflect.ValueOf(scratch).Elem(), path)
+		if i.Merger.Accept(champion, field, m.Source, m.URL, current, incoming) {
+			if errSet := setPath(reflect.ValueOf(champion).Elem(), path, setValue(incoming)); errSet != nil {
+				return errSet
+			}
+		}
+	}
+	return nil
+}
+
+func copyChampion(champion *common.Champion) (*common.Champion, error) {
+	data, errMarshal := json.Marshal(champion)
+	if errMarshal != nil {
+		return nil, errMarshal
+	}
+	var copied common.Champion
+	errUnmarshal := json.Unmarshal(data, &copied)
+	if errUnmarshal != nil {
+		return nil, errUnmarshal
+	}
+	return &copied, nil
+}
+
+// Save writes the champions of result to the output directory
+func (i *Importer) Save(result *Result) error {
+	for _, champion := range result.Updated {
+		if i.Sanitize {
+			if errSanitize := champion.Sanitize(); errSanitize != nil {
+				return errors.Annotatef(errSanitize, "cannot sanitize champion %s", champion.Name)
+			}
+		}
+		errWrite := utils.WriteToFile(filepath.Join(i.OutputDirectory, champion.Filename()), champion)
+		if errWrite != nil {
+			return errWrite
+		}
+	}
+	return nil
+}
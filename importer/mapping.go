@@ -0,0 +1,158 @@
+package importer
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/juju/errors"
+)
+
+//go:embed mappings/*.json
+var builtinMappings embed.FS
+
+// Mapping describes the layout of a sheet and how its columns end up on champions
+type Mapping struct {
+	// Name identifies the mapping, it is the file name of built-in mappings
+	Name string `json:"name"`
+	// Source and URL are recorded as provenance of every imported field
+	Source string `json:"source"`
+	URL    string `json:"url"`
+	// Delimiter separates columns, "," when empty
+	Delimiter string `json:"delimiter"`
+	// Header is the exact first line (columns joined with Delimiter), lines repeating it are skipped
+	Header string `json:"header"`
+	// HeaderAnywhere accepts sheets whose header is not the first line, or is repeated: lines
+	// starting with the first column of Header are headers and must match it, other lines are data
+	HeaderAnywhere bool `json:"header_anywhere"`
+	// Columns is the number of columns every line must have, 0 to not check
+	Columns int `json:"columns"`
+	// Key is the column holding the champion name
+	Key int `json:"key"`
+	// FillDown lists columns that take the value of the previous line when empty
+	FillDown []int `json:"fill_down"`
+	// CreateMissing creates champions that are not found instead of reporting them
+	CreateMissing bool     `json:"create_missing"`
+	Fields        []*Field `json:"fields"`
+	// SkillDetail imports the skill levels described by each line, see SkillDetail
+	SkillDetail *SkillDetail `json:"skill_detail"`
+}
+
+// Field maps one column to a champion field
+type Field struct {
+	Column int `json:"column"`
+	// Path is a dotted path following the json tags of Champion, e.g. "rating.overall", "characteristics.60.hp"
+	// or "skills.0.cooldown". Lists of strings are read from values joined with ListSeparator
+	Path string `json:"path"`
+	// Action is used instead of Path for values that need parsing, see actions
+	Action string `json:"action"`
+	// Transforms are applied in order to the value before it is stored, see transforms
+	Transforms []string `json:"transforms"`
+	// Skip lists values (after transforms) that leave the field untouched
+	Skip []string `json:"skip"`
+	// Provenance is the field recorded as provenance and merged as a whole, the first
+	// element of Path by default
+	Provenance string `json:"provenance"`
+}
+
+const (
+	Action_Aura     = "aura"
+	Action_RawSkill = "raw-skill"
+)
+
+// LoadMapping reads a mapping from a file, or returns the built-in mapping with that name
+func LoadMapping(nameOrFile string) (*Mapping, error) {
+	var reader io.ReadCloser
+	if file, errOpen := os.Open(nameOrFile); errOpen == nil {
+		reader = file
+	} else if builtin, errBuiltin := builtinMappings.Open(fmt.Sprintf("mappings/%s.json", nameOrFile)); errBuiltin == nil {
+		reader = builtin
+	} else {
+		return nil, errors.NotFoundf("mapping %s", nameOrFile)
+	}
+	defer reader.Close()
+	var mapping Mapping
+	errJSON := json.NewDecoder(reader).Decode(&mapping)
+	if errJSON != nil {
+		return nil, errors.Annotatef(errJSON, "cannot unmarshal mapping %s", nameOrFile)
+	}
+	return &mapping, mapping.Validate()
+}
+
+// BuiltinMappings returns the names of mappings shipped with the tools
+func BuiltinMappings() []string {
+	entries, _ := builtinMappings.ReadDir("mappings")
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		names = append(names, strings.TrimSuffix(entry.Name(), ".json"))
+	}
+	return names
+}
+
+// Validate checks the mapping and fills its defaults, it must be called on mappings built in code
+func (m *Mapping) Validate() error {
+	if m.Source == "" {
+		return fmt.Errorf("mapping %s has no source", m.Name)
+	}
+	if m.Delimiter == "" {
+		m.Delimiter = ","
+	} else if len([]rune(m.Delimiter)) != 1 {
+		return fmt.Errorf("mapping %s: delimiter must be a single character", m.Name)
+	}
+	for _, field := range m.Fields {
+		if m.Columns > 0 && field.Column >= m.Columns {
+			return fmt.Errorf("mapping %s: column %d is out of %d columns", m.Name, field.Column, m.Columns)
+		}
+		switch field.Action {
+		case "":
+			if field.Path == "" {
+				return fmt.Errorf("mapping %s: column %d has neither path nor action", m.Name, field.Column)
+			}
+			if field.Provenance == "" {
+				field.Provenance = strings.Split(field.Path, ".")[0]
+			}
+		case Action_Aura:
+			field.Provenance = "auras"
+		case Action_RawSkill:
+			// provenance is recorded per skill
+		default:
+			return fmt.Errorf("mapping %s: unknown action %s", m.Name, field.Action)
+		}
+		for _, name := range field.Transforms {
+			if _, ok := transforms[name]; !ok {
+				return fmt.Errorf("mapping %s: unknown transform %s", m.Name, name)
+			}
+		}
+	}
+	if m.SkillDetail != nil && m.Columns > 0 && m.SkillDetail.lastColumn() >= m.Columns {
+		return fmt.Errorf("mapping %s: skill detail column %d is out of %d columns", m.Name, m.SkillDetail.lastColumn(), m.Columns)
+	}
+	return nil
+}
+
+// CheckHeader returns an error when line is not the header of the sheet. Any line is accepted when
+// the mapping has no header, and with HeaderAnywhere so are lines not starting like the header.
+func (m *Mapping) CheckHeader(line []string) error {
+	if m.HeaderAnywhere && (len(line) == 0 || line[0] != strings.Split(m.Header, m.Delimiter)[0]) {
+		return nil
+	}
+	if joined := strings.Join(line, m.Delimiter); m.Header != "" && joined != m.Header {
+		return fmt.Errorf("invalid first line\n'%s'\nexpected\n'%s'", joined, m.Header)
+	}
+	return nil
+}
+
+// Without removes the fields stored under prefix, e.g. characteristics
+func (m *Mapping) Without(prefix string) {
+	fields := make([]*Field, 0, len(m.Fields))
+	for _, field := range m.Fields {
+		if field.Path == prefix || strings.HasPrefix(field.Path, prefix+".") {
+			continue
+		}
+		fields = append(fields, field)
+	}
+	m.Fields = fields
+}
@@ -0,0 +1,168 @@
+package importer
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// getPath returns the value at path, following json tags, or nil if a part of the path is not set
+func getPath(v reflect.Value, path []string) interface{} {
+	for _, part := range path {
+		for v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				return nil
+			}
+			v = v.Elem()
+		}
+		switch v.Kind() {
+		case reflect.Struct:
+			idx := fieldIndex(v.Type(), part)
+			if idx < 0 {
+				return nil
+			}
+			v = v.Field(idx)
+		case reflect.Map:
+			key, errKey := mapKey(v.Type(), part)
+			if errKey != nil {
+				return nil
+			}
+			v = v.MapIndex(key)
+			if !v.IsValid() {
+				return nil
+			}
+		case reflect.Slice:
+			idx, errIdx := strconv.Atoi(part)
+			if errIdx != nil || idx < 0 || idx >= v.Len() {
+				return nil
+			}
+			v = v.Index(idx)
+		default:
+			return nil
+		}
+	}
+	return v.Interface()
+}
+
+// setPath walks path from v, allocating pointers and maps on the way, and calls set on the value found.
+// Slices are walked by index but never grown.
+func setPath(v reflect.Value, path []string, set func(reflect.Value) error) error {
+	if len(path) == 0 {
+		return set(v)
+	}
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		return setPath(v.Elem(), path, set)
+	case reflect.Struct:
+		idx := fieldIndex(v.Type(), path[0])
+		if idx < 0 {
+			return fmt.Errorf("no field %s in %s", path[0], v.Type())
+		}
+		return setPath(v.Field(idx), path[1:], set)
+	case reflect.Map:
+		key, errKey := mapKey(v.Type(), path[0])
+		if errKey != nil {
+			return errKey
+		}
+		if v.IsNil() {
+			v.Set(reflect.MakeMap(v.Type()))
+		}
+		elem := reflect.New(v.Type().Elem()).Elem()
+		if current := v.MapIndex(key); current.IsValid() {
+			elem.Set(current)
+		}
+		if err := setPath(elem, path[1:], set); err != nil {
+			return err
+		}
+		v.SetMapIndex(key, elem)
+		return nil
+	case reflect.Slice:
+		idx, errIdx := strconv.Atoi(path[0])
+		if errIdx != nil || idx < 0 || idx >= v.Len() {
+			return fmt.Errorf("no element %s in %s of length %d", path[0], v.Type(), v.Len())
+		}
+		return setPath(v.Index(idx), path[1:], set)
+	}
+	return fmt.Errorf("cannot walk through %s with %s", v.Type(), path[0])
+}
+
+// ListSeparator joins the values of string lists held in a single column
+const ListSeparator = "|"
+
+func setString(v reflect.Value, str string) error {
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(str)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		f, err := parseFloat(str)
+		if err != nil {
+			return err
+		}
+		v.SetInt(int64(f))
+	case reflect.Float32, reflect.Float64:
+		f, err := parseFloat(str)
+		if err != nil {
+			return err
+		}
+		v.SetFloat(f)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(str)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Slice:
+		if v.Type().Elem().Kind() != reflect.String {
+			return fmt.Errorf("cannot store a string in %s", v.Type())
+		}
+		list := make([]string, 0)
+		if str != "" {
+			list = strings.Split(str, ListSeparator)
+		}
+		v.Set(reflect.ValueOf(list))
+	default:
+		return fmt.Errorf("cannot store a string in %s", v.Type())
+	}
+	return nil
+}
+
+func setValue(val interface{}) func(reflect.Value) error {
+	return func(v reflect.Value) error {
+		if val == nil {
+			v.Set(reflect.Zero(v.Type()))
+		} else {
+			v.Set(reflect.ValueOf(val))
+		}
+		return nil
+	}
+}
+
+func fieldIndex(t reflect.Type, name string) int {
+	for i := 0; i < t.NumField(); i++ {
+		if strings.Split(t.Field(i).Tag.Get("json"), ",")[0] == name {
+			return i
+		}
+	}
+	return -1
+}
+
+func mapKey(t reflect.Type, str string) (reflect.Value, error) {
+	key := reflect.New(t.Key()).Elem()
+	switch t.Key().Kind() {
+	case reflect.String:
+		key.SetString(str)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(str, 10, 64)
+		if err != nil {
+			return key, fmt.Errorf("invalid key %s for %s", str, t)
+		}
+		key.SetInt(i)
+	default:
+		return key, fmt.Errorf("unsupported key type %s", t.Key())
+	}
+	return key, nil
+}
@@ -0,0 +1,130 @@
+package importer
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/raid-codex/tools/common"
+)
+
+// SkillDetail locates the columns of sheets describing skills level by level, one line per level
+type SkillDetail struct {
+	SkillNumber int `json:"skill_number"`
+	SkillName   int `json:"skill_name"`
+	Level       int `json:"level"`
+	LevelDetail int `json:"level_detail"`
+	BasedOn     int `json:"based_on"`
+	Targets     int `json:"targets"`
+	Hits        int `json:"hits"`
+	Cooldown    int `json:"cooldown"`
+	Who         int `json:"who"`
+	// Effects are the first columns of groups of 6 columns describing an effect: chance, value,
+	// effect, who, turns and the condition placing it
+	Effects []int `json:"effects"`
+}
+
+// lastColumn returns the highest column the detail reads
+func (sd *SkillDetail) lastColumn() int {
+	last := 0
+	for _, column := range []int{sd.SkillNumber, sd.SkillName, sd.Level, sd.LevelDetail, sd.BasedOn, sd.Targets, sd.Hits, sd.Cooldown, sd.Who} {
+		if column > last {
+			last = column
+		}
+	}
+	for _, column := range sd.Effects {
+		if column+5 > last {
+			last = column + 5
+		}
+	}
+	return last
+}
+
+var passiveSuffixes = []string{" [Passive]", " [P]"}
+
+// applySkillDetail stores the level described by line on its skill, the skill is created when the
+// champion does not have it yet
+func (i *Importer) applySkillDetail(champion *common.Champion, line []string) error {
+	sd := i.Mapping.SkillDetail
+	value := func(column int) string {
+		return strings.Trim(line[column], " !")
+	}
+	name, passive := value(sd.SkillName), false
+	for _, suffix := range passiveSuffixes {
+		if strings.Contains(name, suffix) {
+			name, passive = strings.Replace(name, suffix, "", -1), true
+		}
+	}
+	skill, errSkill := champion.GetSkillByName(name)
+	if errSkill != nil {
+		skill = &common.Skill{Name: name}
+		champion.Skills = append(champion.Skills, skill)
+	}
+	skill.SkillNumber = value(sd.SkillNumber)
+	skill.Passive = passive
+	if value(sd.Level) == "-" {
+		return nil
+	}
+	data := &common.SkillData{Level: value(sd.Level), RawDetail: value(sd.LevelDetail)}
+	if basedOn := strings.Split(value(sd.BasedOn), "/"); basedOn[0] != "-" {
+		data.BasedOn = basedOn
+	}
+	hits, errHits := parseInt(value(sd.Hits))
+	if errHits != nil {
+		return errors.Annotate(errHits, "invalid hits")
+	}
+	data.Hits = hits
+	cooldown, errCooldown := parseInt(value(sd.Cooldown))
+	if errCooldown != nil {
+		return errors.Annotate(errCooldown, "invalid cooldown")
+	}
+	data.Cooldown = cooldown
+	data.Target = &common.Target{Who: value(sd.Who), Targets: value(sd.Targets)}
+	for _, column := range sd.Effects {
+		effect := value(column + 2)
+		if effect == "-" {
+			continue
+		}
+		chance, errChance := parseFloat(value(column))
+		if errChance != nil {
+			return errors.Annotatef(errChance, "invalid chance in column %d", column)
+		}
+		amount, raw := int64(1), value(column+1)
+		if strings.HasPrefix(raw, "2x") {
+			amount = 2
+		} else if strings.HasPrefix(raw, "3x") {
+			amount = 3
+		}
+		v, errValue := parseFloat(raw)
+		if errValue != nil {
+			return errors.Annotatef(errValue, "invalid value in column %d", column+1)
+		}
+		turns, errTurns := parseInt(value(column + 4))
+		if errTurns != nil {
+			return errors.Annotatef(errTurns, "invalid turns in column %d", column+4)
+		}
+		data.AddEffect(effect, value(column+3), turns, chance/100.0, value(column+5), v/100.0, amount)
+	}
+	skill.SetSkillData(data)
+	champion.SetProvenance(common.SkillProvenanceField(skill.Name, "upgrades"), i.Mapping.Source, i.Mapping.URL)
+	return nil
+}
+
+func parseInt(str string) (int64, error) {
+	if str == "-" || str == "" {
+		return 0, nil
+	}
+	value, errValue := strconv.ParseInt(str, 10, 64)
+	if errValue != nil {
+		if strings.HasPrefix(str, "x") {
+			return parseInt(str[1:])
+		} else if strings.HasSuffix(str, "(2)") {
+			return parseInt(str[:len(str)-3])
+		} else if strings.HasSuffix(str, " turn") {
+			return parseInt(str[:len(str)-5])
+		}
+		return 0, fmt.Errorf("cannot parse '%s': %s", str, errValue)
+	}
+	return value, nil
+}
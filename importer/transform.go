@@ -0,0 +1,94 @@
+package importer
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/raid-codex/tools/common"
+)
+
+var (
+	transforms = map[string]func(string) (string, error){
+		"trim":        func(v string) (string, error) { return strings.Trim(v, " !"), nil },
+		"slug":        func(v string) (string, error) { return common.GetLinkNameFromSanitizedName(v), nil },
+		"number":      transformNumber,
+		"percent":     transformPercent,
+		"tier-letter": transformTierLetter,
+		"tier-score":  transformTierScore,
+	}
+)
+
+// transformNumber reads the loosely written numbers of community sheets ("x2", "1,5", "3 turn"...)
+func transformNumber(str string) (string, error) {
+	v, err := parseFloat(str)
+	if err != nil {
+		return "", err
+	}
+	return strconv.FormatFloat(v, 'f', -1, 64), nil
+}
+
+// transformPercent turns "15" or "15%" into "0.15"
+func transformPercent(str string) (string, error) {
+	v, err := parseFloat(strings.TrimSuffix(strings.TrimSpace(str), "%"))
+	if err != nil {
+		return "", err
+	}
+	return strconv.FormatFloat(v/100.0, 'f', -1, 64), nil
+}
+
+// transformTierLetter converts the G(od) and T(op) tiers of sheets to our grades
+func transformTierLetter(rating string) (string, error) {
+	switch rating {
+	case "G":
+		return "SS", nil
+	case "T":
+		return "S", nil
+	}
+	return rating, nil
+}
+
+// transformTierScore converts an overall score to a grade
+func transformTierScore(v string) (string, error) {
+	intV, err := strconv.Atoi(v)
+	if err != nil {
+		return "", fmt.Errorf("invalid score %s: %s", v, err)
+	}
+	switch true {
+	case intV < 12:
+		return "D", nil
+	case intV < 22:
+		return "C", nil
+	case intV < 32:
+		return "B", nil
+	case intV < 45:
+		return "A", nil
+	case intV < 63:
+		return "S", nil
+	}
+	return "SS", nil
+}
+
+func parseFloat(str string) (float64, error) {
+	if str == "-" || str == "" {
+		return 0.0, nil
+	}
+	value, errValue := strconv.ParseFloat(str, 64)
+	if errValue != nil {
+		if strings.HasPrefix(str, "x") {
+			return parseFloat(str[1:])
+		} else if strings.Contains(str, ",") {
+			return parseFloat(strings.Replace(str, ",", ".", -1))
+		} else if strings.HasSuffix(str, " turn") {
+			return parseFloat(str[:len(str)-5])
+		} else if strings.HasSuffix(str, " Turn") {
+			return parseFloat(str[:len(str)-5])
+		} else if strings.HasPrefix(str, "2x") || strings.HasPrefix(str, "3x") {
+			return parseFloat(str[2:])
+		} else if strings.HasSuffix(str, "+") {
+			return parseFloat(str[:len(str)-1])
+		}
+		return 0, fmt.Errorf("cannot parse %s: %s", str, errValue)
+	}
+	return value, nil
+}
@@ -0,0 +1,174 @@
+package media
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/raid-codex/tools/common"
+)
+
+// WebPEncoder writes the WebP variant of an image file
+type WebPEncoder interface {
+	Encode(src, dst string) error
+}
+
+// CWebP runs cwebp, from libwebp, as the standard library has no WebP encoder
+type CWebP struct {
+	// Binary is the path of cwebp, looked up in PATH when empty
+	Binary  string
+	Quality int
+}
+
+func (c *CWebP) Encode(src, dst string) error {
+	binary := c.Binary
+	if binary == "" {
+		binary = "cwebp"
+	}
+	args := []string{"-quiet"}
+	if c.Quality > 0 {
+		args = append(args, "-q", strconv.Itoa(c.Quality))
+	}
+	args = append(args, src, "-o", dst)
+	var stderr bytes.Buffer
+	cmd := exec.Command(binary, args...)
+	cmd.Stderr = &stderr
+	if errRun := cmd.Run(); errRun != nil {
+		return fmt.Errorf("%s: %s %s", binary, errRun, strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
+
+// Generator derives the variants of the champion images from their portrait
+type Generator struct {
+	// Folder is laid out like wp-content/uploads, the portraits are read from champions/ and the
+	// variants are written where the templates link to them
+	Folder string
+	// WebP writes the WebP variants, they are skipped when nil
+	WebP WebPEncoder
+	// Force writes the variants even when they are newer than the portrait
+	Force bool
+}
+
+// Generated lists the variants of a portrait, by path relative to the folder
+type Generated struct {
+	Written  []string
+	UpToDate []string
+	// Skipped are the resized portraits at least as wide as the portrait, which is never upscaled. The
+	// small image and the hashed thumbnail are written whatever the width of the portrait.
+	Skipped []string
+}
+
+// Champion writes the variants of the portrait of the champion, the error is a not found error when
+// the champion has no portrait
+func (g *Generator) Champion(champion *common.Champion) (*Generated, error) {
+	portrait := common.ChampionImagePath(champion.Slug)
+	portraitFile := filepath.Join(g.Folder, filepath.FromSlash(portrait))
+	info, errStat := os.Stat(portraitFile)
+	if os.IsNotExist(errStat) {
+		return nil, errors.NotFoundf("portrait %s of %s", portrait, champion.Slug)
+	} else if errStat != nil {
+		return nil, errStat
+	}
+	var img image.Image
+	decode := func() (image.Image, error) {
+		if img != nil {
+			return img, nil
+		}
+		file, errOpen := os.Open(portraitFile)
+		if errOpen != nil {
+			return nil, errOpen
+		}
+		defer file.Close()
+		decoded, _, errDecode := image.Decode(file)
+		if errDecode != nil {
+			return nil, errors.Annotatef(errDecode, "cannot decode %s", portraitFile)
+		}
+		img = decoded
+		return img, nil
+	}
+	generated := &Generated{}
+	upToDate := func(p string) bool {
+		if g.Force {
+			return false
+		}
+		variant, errVariant := os.Stat(filepath.Join(g.Folder, filepath.FromSlash(p)))
+		return errVariant == nil && !variant.ModTime().Before(info.ModTime())
+	}
+	webp := func(p string) error {
+		if g.WebP == nil {
+			return nil
+		}
+		dst := WebPPath(p)
+		if upToDate(dst) {
+			generated.UpToDate = append(generated.UpToDate, dst)
+			return nil
+		}
+		errEncode := g.WebP.Encode(filepath.Join(g.Folder, filepath.FromSlash(p)), filepath.Join(g.Folder, filepath.FromSlash(dst)))
+		if errEncode != nil {
+			return errors.Annotatef(errEncode, "cannot write %s", dst)
+		}
+		generated.Written = append(generated.Written, dst)
+		return nil
+	}
+	for _, variant := range ChampionVariants(champion) {
+		if upToDate(variant.Path) {
+			generated.UpToDate = append(generated.UpToDate, variant.Path)
+		} else {
+			src, errDecode := decode()
+			if errDecode != nil {
+				return nil, errDecode
+			}
+			if variant.Path == SizedPath(portrait, variant.Width) && variant.Width >= src.Bounds().Dx() {
+				generated.Skipped = append(generated.Skipped, variant.Path)
+				continue
+			}
+			if errWrite := g.write(variant.Path, ResizeToWidth(src, variant.Width)); errWrite != nil {
+				return nil, errWrite
+			}
+			generated.Written = append(generated.Written, variant.Path)
+		}
+		if errWebP := webp(variant.Path); errWebP != nil {
+			return nil, errWebP
+		}
+	}
+	if errWebP := webp(portrait); errWebP != nil {
+		return nil, errWebP
+	}
+	return generated, nil
+}
+
+// write encodes img to the path, as a png or a jpeg depending on its extension
+func (g *Generator) write(p string, img image.Image) error {
+	full := filepath.Join(g.Folder, filepath.FromSlash(p))
+	if errDir := os.MkdirAll(filepath.Dir(full), 0755); errDir != nil {
+		return errDir
+	}
+	out, errCreate := os.Create(full)
+	if errCreate != nil {
+		return errCreate
+	}
+	defer out.Close()
+	var errEncode error
+	switch path.Ext(p) {
+	case ".png":
+		errEncode = png.Encode(out, img)
+	case ".jpg", ".jpeg":
+		errEncode = jpeg.Encode(out, img, &jpeg.Options{Quality: 90})
+	default:
+		errEncode = errors.NotSupportedf("image format of %s", p)
+	}
+	if errEncode != nil {
+		return errors.Annotatef(errEncode, "cannot write %s", full)
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+package media
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"path"
+	"strings"
+
+	"github.com/raid-codex/tools/common"
+)
+
+// ThumbnailSize is the width of the small images and of the hashed thumbnails of the champions
+const ThumbnailSize = 150
+
+// ChampionImageWidths are the widths of the resized portraits listed in the srcset of the champion
+// images, on top of the portrait itself
+var ChampionImageWidths = []int{150, 300}
+
+// ChampionSmallImagePath returns the path of the small image of a champion
+func ChampionSmallImagePath(slug string) string {
+	return fmt.Sprintf("champion-thumbnails/image-champion-small-%s.jpg", slug)
+}
+
+// ChampionThumbnailPath returns the path of the hashed thumbnail of a champion, named after the md5
+// of its giid by Champion.Sanitize, or an empty string when the champion has no giid
+func ChampionThumbnailPath(thumbnail string) string {
+	if thumbnail == "" || thumbnail == "unknown" {
+		return ""
+	}
+	return fmt.Sprintf("hashed-img/%s.png", thumbnail)
+}
+
+// SizedPath returns the path of the image resized to width, e.g. champions/image-champion-kael-300w.jpg
+func SizedPath(p string, width int) string {
+	ext := path.Ext(p)
+	return fmt.Sprintf("%s-%dw%s", strings.TrimSuffix(p, ext), width, ext)
+}
+
+// WebPPath returns the path of the WebP variant of the image
+func WebPPath(p string) string {
+	return fmt.Sprintf("%s.webp", strings.TrimSuffix(p, path.Ext(p)))
+}
+
+// Variant is an image derived from the portrait of a champion
+type Variant struct {
+	Path  string
+	Width int
+}
+
+// ChampionVariants returns the images derived from the portrait of a champion, except their WebP
+// variants: the resized portraits, the small image and the hashed thumbnail
+func ChampionVariants(champion *common.Champion) []Variant {
+	portrait := common.ChampionImagePath(champion.Slug)
+	variants := make([]Variant, 0, len(ChampionImageWidths)+2)
+	for _, width := range ChampionImageWidths {
+		variants = append(variants, Variant{Path: SizedPath(portrait, width), Width: width})
+	}
+	variants = append(variants, Variant{Path: ChampionSmallImagePath(champion.Slug), Width: ThumbnailSize})
+	if thumbnail := ChampionThumbnailPath(champion.Thumbnail); thumbnail != "" {
+		variants = append(variants, Variant{Path: thumbnail, Width: ThumbnailSize})
+	}
+	return variants
+}
+
+// Resize scales img to width x height, averaging the pixels each pixel of the result covers
+func Resize(img image.Image, width, height int) image.Image {
+	bounds := img.Bounds()
+	resized := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		y0 := bounds.Min.Y + y*bounds.Dy()/height
+		y1 := bounds.Min.Y + (y+1)*bounds.Dy()/height
+		if y1 == y0 {
+			y1++
+		}
+		for x := 0; x < width; x++ {
+			x0 := bounds.Min.X + x*bounds.Dx()/width
+			x1 := bounds.Min.X + (x+1)*bounds.Dx()/width
+			if x1 == x0 {
+				x1++
+			}
+			var r, g, b, a, n uint32
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					pr, pg, pb, pa := img.At(sx, sy).RGBA()
+					r, g, b, a, n = r+pr, g+pg, b+pb, a+pa, n+1
+				}
+			}
+			resized.Set(x, y, color.RGBA64{R: uint16(r / n), G: uint16(g / n), B: uint16(b / n), A: uint16(a / n)})
+		}
+	}
+	return resized
+}
+
+// ResizeToWidth scales img to width, keeping its proportions
+func ResizeToWidth(img image.Image, width int) image.Image {
+	bounds := img.Bounds()
+	height := bounds.Dy() * width / bounds.Dx()
+	if height < 1 {
+		height = 1
+	}
+	return Resize(img, width, height)
+}
@@ -0,0 +1,92 @@
+package media
+
+import (
+	"encoding/json"
+	"image"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/juju/errors"
+	"github.com/raid-codex/tools/utils"
+)
+
+// Manifest lists the images available on the website, by path relative to wp-content/uploads, with
+// their width. It is stored as a JSON object of the widths by path, the width is 0 when the image
+// could not be decoded, as for WebP images.
+type Manifest struct {
+	widths map[string]int
+}
+
+// BuildManifest lists the files of a local mirror of wp-content/uploads
+func BuildManifest(uploadsFolder string) (*Manifest, error) {
+	paths, errScan := scan(uploadsFolder)
+	if errScan != nil {
+		return nil, errors.Annotatef(errScan, "cannot scan %s", uploadsFolder)
+	}
+	manifest := &Manifest{widths: make(map[string]int, len(paths))}
+	for path := range paths {
+		manifest.widths[path] = imageWidth(filepath.Join(uploadsFolder, filepath.FromSlash(path)))
+	}
+	return manifest, nil
+}
+
+// imageWidth returns the width of the image file, 0 when it cannot be decoded
+func imageWidth(filename string) int {
+	file, errOpen := os.Open(filename)
+	if errOpen != nil {
+		return 0
+	}
+	defer file.Close()
+	config, _, errConfig := image.DecodeConfig(file)
+	if errConfig != nil {
+		return 0
+	}
+	return config.Width
+}
+
+// LoadManifest reads a manifest written by Save, manifests listing the paths only are read with no
+// width
+func LoadManifest(filename string) (*Manifest, error) {
+	data, errRead := ioutil.ReadFile(filename)
+	if errRead != nil {
+		return nil, errRead
+	}
+	manifest := &Manifest{widths: map[string]int{}}
+	if errJSON := json.Unmarshal(data, &manifest.widths); errJSON != nil {
+		var list []string
+		if json.Unmarshal(data, &list) != nil {
+			return nil, errors.Annotatef(errJSON, "cannot read manifest %s", filename)
+		}
+		for _, path := range list {
+			manifest.widths[path] = 0
+		}
+	}
+	return manifest, nil
+}
+
+// Has tells whether the image at path is available
+func (m *Manifest) Has(path string) bool {
+	_, ok := m.widths[path]
+	return ok
+}
+
+// Width returns the width of the image at path, 0 when it is unknown or the image is not available
+func (m *Manifest) Width(path string) int {
+	return m.widths[path]
+}
+
+// Paths returns the sorted paths of the images
+func (m *Manifest) Paths() []string {
+	list := make([]string, 0, len(m.widths))
+	for path := range m.widths {
+		list = append(list, path)
+	}
+	sort.Strings(list)
+	return list
+}
+
+func (m *Manifest) Save(filename string) error {
+	return utils.WriteToFile(filename, m.widths)
+}
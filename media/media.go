@@ -0,0 +1,244 @@
+package media
+
+import (
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/raid-codex/tools/common"
+	"github.com/raid-codex/tools/publish"
+)
+
+// Reference is an image the templates link to, Path is relative to wp-content/uploads
+type Reference struct {
+	Path string
+	// Owner describes the entity using the image, e.g. "champion kael"
+	Owner string
+	// Derived images are generated from another image by website media generate, they are not
+	// reported missing as the templates only link to the ones that exist
+	Derived bool
+}
+
+// References returns the images of the entities of the factory, sorted by path
+func References() ([]Reference, error) {
+	refs := map[string]Reference{}
+	add := func(path, owner string) {
+		if _, ok := refs[path]; !ok {
+			refs[path] = Reference{Path: path, Owner: owner}
+		}
+	}
+	derived := func(path, owner string) {
+		if _, ok := refs[path]; !ok {
+			refs[path] = Reference{Path: path, Owner: owner, Derived: true}
+		}
+	}
+	champions, errChampions := common.GetChampions()
+	if errChampions != nil {
+		return nil, errChampions
+	}
+	for _, champion := range champions {
+		owner := fmt.Sprintf("champion %s", champion.Slug)
+		portrait := common.ChampionImagePath(champion.Slug)
+		add(portrait, owner)
+		// the templates link to the small image whether it exists or not
+		add(ChampionSmallImagePath(champion.Slug), owner)
+		if thumbnail := ChampionThumbnailPath(champion.Thumbnail); thumbnail != "" {
+			add(thumbnail, owner)
+		}
+		derived(WebPPath(portrait), owner)
+		for _, variant := range ChampionVariants(champion) {
+			derived(variant.Path, owner)
+			derived(WebPPath(variant.Path), owner)
+		}
+		for _, skill := range champion.Skills {
+			if skill.ImageSlug != "" {
+				add(common.SkillImagePath(skill.ImageSlug), fmt.Sprintf("skill %s of champion %s", skill.Slug, champion.Slug))
+			}
+		}
+	}
+	factions, errFactions := common.GetFactions()
+	if errFactions != nil {
+		return nil, errFactions
+	}
+	for _, faction := range factions {
+		if faction.ImageSlug != "" {
+			add(common.FactionImagePath(faction.ImageSlug), fmt.Sprintf("faction %s", faction.Slug))
+		}
+	}
+	effects, errEffects := common.GetStatuseffects()
+	if errEffects != nil {
+		return nil, errEffects
+	}
+	for _, effect := range effects {
+		if effect.ImageSlug != "" {
+			add(common.StatusEffectImagePath(effect.ImageSlug), fmt.Sprintf("status effect %s", effect.Slug))
+		}
+	}
+	list := make([]Reference, 0, len(refs))
+	for _, ref := range refs {
+		list = append(list, ref)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Path < list[j].Path })
+	return list, nil
+}
+
+// Report tells what a sync found and did
+type Report struct {
+	// Missing are the references without a file in the image folder, derived images excepted
+	Missing []Reference
+	// Orphans are the files of the image folder no entity references
+	Orphans []string
+	// Uploaded are the references uploaded, or that would be uploaded on a dry run
+	Uploaded []Reference
+	// Failed are the references that could not be checked or uploaded
+	Failed []Reference
+	DryRun bool
+}
+
+func (r *Report) String() string {
+	lines := make([]string, 0)
+	describe := func(title string, refs []Reference) {
+		if len(refs) == 0 {
+			return
+		}
+		lines = append(lines, fmt.Sprintf("%d %s:", len(refs), title))
+		for _, ref := range refs {
+			lines = append(lines, fmt.Sprintf("\t%s (%s)", ref.Path, ref.Owner))
+		}
+	}
+	describe("images missing from the image folder", r.Missing)
+	if r.DryRun {
+		describe("images to upload", r.Uploaded)
+	} else {
+		describe("images uploaded", r.Uploaded)
+	}
+	describe("images failed", r.Failed)
+	if len(r.Orphans) > 0 {
+		lines = append(lines, fmt.Sprintf("%d images used by no entity:", len(r.Orphans)))
+		for _, orphan := range r.Orphans {
+			lines = append(lines, fmt.Sprintf("\t%s", orphan))
+		}
+	}
+	if len(lines) == 0 {
+		return "every image is in the image folder and published"
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Sync checks every reference has a file in Folder, laid out like wp-content/uploads, and uploads
+// the ones the publisher does not serve yet
+type Sync struct {
+	Folder    string
+	Publisher publish.Publisher
+	// DryRun only reports the images to upload
+	DryRun  bool
+	Workers int
+}
+
+// Run checks the references against the image folder and the publisher
+func (s *Sync) Run(refs []Reference) (*Report, error) {
+	files, errFiles := scan(s.Folder)
+	if errFiles != nil {
+		return nil, errFiles
+	}
+	report := &Report{DryRun: s.DryRun}
+	referenced := map[string]bool{}
+	toUpload := make([]Reference, 0)
+	for _, ref := range refs {
+		referenced[ref.Path] = true
+		if !files[ref.Path] {
+			if !ref.Derived {
+				report.Missing = append(report.Missing, ref)
+			}
+			continue
+		}
+		toUpload = append(toUpload, ref)
+	}
+	for file := range files {
+		if !referenced[file] {
+			report.Orphans = append(report.Orphans, file)
+		}
+	}
+	sort.Strings(report.Orphans)
+	jobs := make(chan Reference)
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	workers := s.Workers
+	if workers < 1 {
+		workers = 1
+	}
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for ref := range jobs {
+				uploaded, errUpload := s.upload(ref)
+				mu.Lock()
+				if errUpload != nil {
+					log.Printf("%s: %v\n", ref.Path, errUpload)
+					report.Failed = append(report.Failed, ref)
+				} else if uploaded {
+					report.Uploaded = append(report.Uploaded, ref)
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	for _, ref := range toUpload {
+		jobs <- ref
+	}
+	close(jobs)
+	wg.Wait()
+	for _, refs := range [][]Reference{report.Uploaded, report.Failed} {
+		sort.Slice(refs, func(i, j int) bool { return refs[i].Path < refs[j].Path })
+	}
+	return report, nil
+}
+
+// upload uploads the image unless the publisher already serves it, and tells whether it did
+func (s *Sync) upload(ref Reference) (bool, error) {
+	exists, errExists := s.Publisher.MediaExists(ref.Path)
+	if errExists != nil {
+		return false, errExists
+	} else if exists {
+		return false, nil
+	} else if s.DryRun {
+		return true, nil
+	}
+	data, errRead := ioutil.ReadFile(filepath.Join(s.Folder, filepath.FromSlash(ref.Path)))
+	if errRead != nil {
+		return false, errRead
+	}
+	return true, s.Publisher.UploadMedia(ref.Path, data)
+}
+
+// scan returns the files of the folder, hidden ones excepted, by path relative to the folder
+func scan(folder string) (map[string]bool, error) {
+	files := map[string]bool{}
+	errWalk := filepath.Walk(folder, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if strings.HasPrefix(info.Name(), ".") && path != folder {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if info.IsDir() {
+			return nil
+		}
+		rel, errRel := filepath.Rel(folder, path)
+		if errRel != nil {
+			return errRel
+		}
+		files[filepath.ToSlash(rel)] = true
+		return nil
+	})
+	return files, errWalk
+}
@@ -0,0 +1,201 @@
+package media
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/raid-codex/tools/common"
+	"github.com/raid-codex/tools/utils"
+)
+
+// UploadsMarker is the part of the url of an image preceding its path in the manifest
+const UploadsMarker = common.UploadsPath
+
+// MissingImageTTL is how long an image found missing is not probed again, images found are never
+// probed again
+const MissingImageTTL = 24 * time.Hour
+
+// Resolver picks the first available image of a list of candidates. With a manifest, images are
+// looked up in the manifest only. Without one they are probed over HTTP when probing is enabled, and
+// the results are kept in the cache file when there is one so the next runs do not probe them again.
+// Otherwise every image is assumed available and the first candidate is picked.
+type Resolver struct {
+	manifest  *Manifest
+	probe     bool
+	cacheFile string
+
+	mu    sync.Mutex
+	cache map[string]probeResult
+	// unreachable are the images that could not be probed, they are not saved to the cache file
+	unreachable map[string]bool
+	missing     map[string]int
+}
+
+// probeResult is what probing an image found, and when
+type probeResult struct {
+	Found     bool      `json:"found"`
+	CheckedAt time.Time `json:"checked_at"`
+}
+
+// expired tells whether the image should be probed again
+func (pr probeResult) expired(now time.Time) bool {
+	return !pr.Found && now.Sub(pr.CheckedAt) >= MissingImageTTL
+}
+
+// NewResolver returns a resolver checking images against manifest, or probing them over HTTP when
+// manifest is nil and probe is set. Probe results are read from and saved to cacheFile when it is set.
+func NewResolver(manifest *Manifest, probe bool, cacheFile string) (*Resolver, error) {
+	r := &Resolver{
+		manifest:    manifest,
+		probe:       probe,
+		cacheFile:   cacheFile,
+		cache:       map[string]probeResult{},
+		unreachable: map[string]bool{},
+		missing:     map[string]int{},
+	}
+	if cacheFile == "" || manifest != nil || !probe {
+		return r, nil
+	}
+	data, errRead := ioutil.ReadFile(cacheFile)
+	if os.IsNotExist(errRead) {
+		return r, nil
+	} else if errRead != nil {
+		return nil, errRead
+	}
+	if errJSON := json.Unmarshal(data, &r.cache); errJSON != nil {
+		return nil, fmt.Errorf("cannot read image cache %s: %s", cacheFile, errJSON)
+	}
+	return r, nil
+}
+
+// ResolverFromEnv returns the resolver of the rendering commands, checking images against the
+// manifest file, IMAGE_MANIFEST when empty, or probing them over HTTP when there is no manifest and
+// probe is set. IMAGE_CACHE is the file keeping the results of the probes.
+func ResolverFromEnv(manifestFile string, probe bool) (*Resolver, error) {
+	if manifestFile == "" {
+		manifestFile = os.Getenv("IMAGE_MANIFEST")
+	}
+	var manifest *Manifest
+	if manifestFile != "" {
+		var errManifest error
+		manifest, errManifest = LoadManifest(manifestFile)
+		if errManifest != nil {
+			return nil, errManifest
+		}
+	}
+	return NewResolver(manifest, probe, os.Getenv("IMAGE_CACHE"))
+}
+
+// Manifest returns the manifest the images are looked up in, nil when they are probed
+func (r *Resolver) Manifest() *Manifest {
+	return r.manifest
+}
+
+// Resolve returns the first available url, data urls are always available. The first candidate is
+// reported as missing when another one is returned.
+func (r *Resolver) Resolve(urls ...string) (string, error) {
+	for idx, url := range urls {
+		if r.Available(url) {
+			if idx > 0 {
+				r.mu.Lock()
+				r.missing[urls[0]]++
+				r.mu.Unlock()
+			}
+			return url, nil
+		}
+	}
+	return "", fmt.Errorf("no available image in %s", strings.Join(urls, ", "))
+}
+
+// Available tells whether the image is available, an image not available is not reported missing
+func (r *Resolver) Available(url string) bool {
+	if strings.HasPrefix(url, "data:image/") {
+		return true
+	}
+	if r.manifest != nil {
+		idx := strings.Index(url, UploadsMarker)
+		return idx >= 0 && r.manifest.Has(url[idx+len(UploadsMarker):])
+	} else if !r.probe {
+		return true
+	}
+	r.mu.Lock()
+	v, ok := r.cache[url]
+	unreachable := r.unreachable[url]
+	r.mu.Unlock()
+	if ok && !v.expired(time.Now()) {
+		return v.Found
+	} else if unreachable {
+		return false
+	}
+	found, errProbe := probe(url)
+	if errProbe != nil {
+		// the website could not be reached, the next run probes the image again
+		log.Printf("url: %s -> %v\n", url, errProbe)
+		r.mu.Lock()
+		r.unreachable[url] = true
+		r.mu.Unlock()
+		return false
+	}
+	r.mu.Lock()
+	r.cache[url] = probeResult{Found: found, CheckedAt: time.Now()}
+	r.mu.Unlock()
+	return found
+}
+
+// probe tells whether the website serves the image
+func probe(url string) (bool, error) {
+	resp, err := http.Head(url)
+	if err != nil {
+		return false, err
+	}
+	resp.Body.Close()
+	return resp.StatusCode != http.StatusNotFound, nil
+}
+
+// Missing returns the preferred images that were not available, with the number of times a
+// fallback was used instead
+func (r *Resolver) Missing() map[string]int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	missing := make(map[string]int, len(r.missing))
+	for url, count := range r.missing {
+		missing[url] = count
+	}
+	return missing
+}
+
+// Report describes the missing images, it is empty when every preferred image was available
+func (r *Resolver) Report() string {
+	missing := r.Missing()
+	if len(missing) == 0 {
+		return ""
+	}
+	urls := make([]string, 0, len(missing))
+	for url := range missing {
+		urls = append(urls, url)
+	}
+	sort.Strings(urls)
+	lines := []string{fmt.Sprintf("%d images missing:", len(urls))}
+	for _, url := range urls {
+		lines = append(lines, fmt.Sprintf("\t%s (replaced %d times)", url, missing[url]))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Save writes the results of HTTP probes to the cache file, if any
+func (r *Resolver) Save() error {
+	if r.cacheFile == "" || r.manifest != nil || !r.probe {
+		return nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return utils.WriteToFile(r.cacheFile, r.cache)
+}
@@ -0,0 +1,127 @@
+package publish
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/raid-codex/tools/seo"
+	"github.com/raid-codex/tools/utils"
+)
+
+// Filesystem stores every page as <directory>/<slug>.json and media under <directory>/media, useful
+// to inspect what would be published
+type Filesystem struct {
+	Directory string
+}
+
+// NewFilesystem returns a publisher writing pages to directory
+func NewFilesystem(directory string) *Filesystem {
+	return &Filesystem{Directory: directory}
+}
+
+func (fs *Filesystem) filename(slug string) string {
+	return filepath.Join(fs.Directory, fmt.Sprintf("%s.json", slug))
+}
+
+func (fs *Filesystem) GetPage(slug string) (*Page, error) {
+	data, errRead := ioutil.ReadFile(fs.filename(slug))
+	if os.IsNotExist(errRead) {
+		return nil, errors.NotFoundf("page %s", slug)
+	} else if errRead != nil {
+		return nil, errRead
+	}
+	var page Page
+	if errJSON := json.Unmarshal(data, &page); errJSON != nil {
+		return nil, errors.Annotatef(errJSON, "cannot read page %s", slug)
+	}
+	return &page, nil
+}
+
+func (fs *Filesystem) write(page *Page) error {
+	if errDir := os.MkdirAll(fs.Directory, 0755); errDir != nil {
+		return errDir
+	}
+	return utils.WriteToFile(fs.filename(page.Slug), page)
+}
+
+func (fs *Filesystem) CreatePage(page *Page) error {
+	if _, errStat := os.Stat(fs.filename(page.Slug)); errStat == nil {
+		return errors.AlreadyExistsf("page %s", page.Slug)
+	}
+	return fs.write(page)
+}
+
+func (fs *Filesystem) UpdatePage(page *Page) error {
+	existing, errPage := fs.GetPage(page.Slug)
+	if errPage != nil {
+		return errPage
+	}
+	existing.Content = page.Content
+	existing.Excerpt = page.Excerpt
+	existing.Template = page.Template
+	if page.Status != "" {
+		existing.Status = page.Status
+		existing.Date = page.Date
+	}
+	return fs.write(existing)
+}
+
+func (fs *Filesystem) DeletePage(slug string) error {
+	errRemove := os.Remove(fs.filename(slug))
+	if os.IsNotExist(errRemove) {
+		return errors.NotFoundf("page %s", slug)
+	}
+	return errRemove
+}
+
+func (fs *Filesystem) ListPages(parent int) ([]*Page, error) {
+	files, errFiles := filepath.Glob(filepath.Join(fs.Directory, "*.json"))
+	if errFiles != nil {
+		return nil, errFiles
+	}
+	pages := make([]*Page, 0)
+	for _, file := range files {
+		page, errPage := fs.GetPage(strings.TrimSuffix(filepath.Base(file), ".json"))
+		if errPage != nil {
+			return nil, errPage
+		}
+		if page.Parent == parent {
+			pages = append(pages, page)
+		}
+	}
+	return pages, nil
+}
+
+func (fs *Filesystem) ApplySEO(slug string, s *seo.SEO) error {
+	page, errPage := fs.GetPage(slug)
+	if errPage != nil {
+		return errors.Annotate(errPage, "cannot load page")
+	}
+	page.SEO = s
+	return fs.write(page)
+}
+
+func (fs *Filesystem) mediaFilename(path string) string {
+	return filepath.Join(fs.Directory, "media", filepath.FromSlash(path))
+}
+
+func (fs *Filesystem) MediaExists(path string) (bool, error) {
+	_, errStat := os.Stat(fs.mediaFilename(path))
+	if os.IsNotExist(errStat) {
+		return false, nil
+	}
+	return errStat == nil, errStat
+}
+
+func (fs *Filesystem) UploadMedia(path string, data []byte) error {
+	filename := fs.mediaFilename(path)
+	if errDir := os.MkdirAll(filepath.Dir(filename), 0755); errDir != nil {
+		return errDir
+	}
+	return ioutil.WriteFile(filename, data, 0644)
+}
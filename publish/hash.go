@@ -0,0 +1,185 @@
+package publish
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/raid-codex/tools/utils"
+)
+
+// DefaultHashFile is the file the content hashes are kept in when PUBLISH_HASH_FILE is not set
+const DefaultHashFile = "publish-hashes.json"
+
+// Hash returns the hash of everything a publisher stores for the page, SEO metadata excluded. The
+// status and date are only part of the hash when set.
+func Hash(page *Page) string {
+	h := sha256.New()
+	fields := []string{page.Title, page.Template, fmt.Sprintf("%d", page.Parent), page.Excerpt, page.Content}
+	if page.Status != "" {
+		fields = append(fields, page.Status)
+	}
+	if page.Date != nil {
+		fields = append(fields, page.Date.UTC().Format(time.RFC3339))
+	}
+	for _, field := range fields {
+		fmt.Fprintf(h, "%d:%s", len(field), field)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// HashStore keeps the hash of the last published version of every page, by slug. The file holds the
+// hashes of every target published to, a store only reads and replaces the hashes of its own.
+type HashStore struct {
+	filename  string
+	namespace string
+	mu        sync.Mutex
+	hashes    map[string]string
+}
+
+// HashNamespace identifies where a publisher stores its pages, e.g. wordpress:https://raid-codex.com
+func HashNamespace(backend, location string) string {
+	return fmt.Sprintf("%s:%s", backend, location)
+}
+
+// readHashes returns the hashes of every namespace in filename
+func readHashes(filename string) (map[string]map[string]string, error) {
+	namespaces := map[string]map[string]string{}
+	data, errRead := ioutil.ReadFile(filename)
+	if os.IsNotExist(errRead) {
+		return namespaces, nil
+	} else if errRead != nil {
+		return nil, errRead
+	}
+	if errJSON := json.Unmarshal(data, &namespaces); errJSON != nil {
+		return nil, fmt.Errorf("cannot read hashes from %s: %s", filename, errJSON)
+	}
+	return namespaces, nil
+}
+
+// LoadHashStore reads the hashes of namespace from filename, the store is empty when the file does
+// not exist
+func LoadHashStore(filename, namespace string) (*HashStore, error) {
+	namespaces, errRead := readHashes(filename)
+	if errRead != nil {
+		return nil, errRead
+	}
+	store := &HashStore{filename: filename, namespace: namespace, hashes: namespaces[namespace]}
+	if store.hashes == nil {
+		store.hashes = map[string]string{}
+	}
+	return store, nil
+}
+
+// HashStoreFromEnv loads the store from PUBLISH_HASH_FILE, or DefaultHashFile, for the publisher
+// FromEnv returns
+func HashStoreFromEnv() (*HashStore, error) {
+	filename := os.Getenv("PUBLISH_HASH_FILE")
+	if filename == "" {
+		filename = DefaultHashFile
+	}
+	backend, location, errTarget := targetFromEnv()
+	if errTarget != nil {
+		return nil, errTarget
+	}
+	if backend == Backend_Filesystem {
+		absolute, errAbs := filepath.Abs(location)
+		if errAbs != nil {
+			return nil, errAbs
+		}
+		location = absolute
+	}
+	return LoadHashStore(filename, HashNamespace(backend, location))
+}
+
+func (hs *HashStore) Get(slug string) string {
+	hs.mu.Lock()
+	defer hs.mu.Unlock()
+	return hs.hashes[slug]
+}
+
+func (hs *HashStore) Set(slug, hash string) {
+	hs.mu.Lock()
+	defer hs.mu.Unlock()
+	hs.hashes[slug] = hash
+}
+
+func (hs *HashStore) Delete(slug string) {
+	hs.mu.Lock()
+	defer hs.mu.Unlock()
+	delete(hs.hashes, slug)
+}
+
+// Reset forgets every hash, so every page is published again
+func (hs *HashStore) Reset() {
+	hs.mu.Lock()
+	defer hs.mu.Unlock()
+	hs.hashes = map[string]string{}
+}
+
+// Save writes the hashes back to the file they were loaded from, the hashes of the other namespaces
+// are read again so that they are kept
+func (hs *HashStore) Save() error {
+	hs.mu.Lock()
+	defer hs.mu.Unlock()
+	namespaces, errRead := readHashes(hs.filename)
+	if errRead != nil {
+		return errRead
+	}
+	namespaces[hs.namespace] = hs.hashes
+	return utils.WriteToFile(hs.filename, namespaces)
+}
+
+// Result is what publishing a page did
+type Result string
+
+const (
+	Result_Created   Result = "created"
+	Result_Updated   Result = "updated"
+	Result_Unchanged Result = "unchanged"
+)
+
+// Summary collects the slugs of the published pages by result
+type Summary struct {
+	mu    sync.Mutex
+	pages map[Result][]string
+}
+
+func (s *Summary) Add(slug string, result Result) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.pages == nil {
+		s.pages = map[Result][]string{}
+	}
+	s.pages[result] = append(s.pages[result], slug)
+}
+
+// Pages returns the sorted slugs of the pages with the result
+func (s *Summary) Pages(result Result) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	pages := append([]string{}, s.pages[result]...)
+	sort.Strings(pages)
+	return pages
+}
+
+func (s *Summary) String() string {
+	lines := make([]string, 0)
+	counts := make([]string, 0)
+	for _, result := range []Result{Result_Created, Result_Updated, Result_Unchanged} {
+		pages := s.Pages(result)
+		counts = append(counts, fmt.Sprintf("%d %s", len(pages), result))
+		if result != Result_Unchanged && len(pages) > 0 {
+			lines = append(lines, fmt.Sprintf("%s: %s", result, strings.Join(pages, ", ")))
+		}
+	}
+	return strings.Join(append(lines, strings.Join(counts, ", ")), "\n")
+}
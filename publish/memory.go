@@ -0,0 +1,112 @@
+package publish
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/juju/errors"
+	"github.com/raid-codex/tools/seo"
+)
+
+// Memory keeps pages in memory, it is a fake for tests and dry runs
+type Memory struct {
+	mu     sync.Mutex
+	lastID int
+	pages  map[string]*Page
+	media  map[string][]byte
+}
+
+// NewMemory returns an empty in-memory publisher
+func NewMemory() *Memory {
+	return &Memory{pages: map[string]*Page{}, media: map[string][]byte{}}
+}
+
+func (m *Memory) GetPage(slug string) (*Page, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	page, ok := m.pages[slug]
+	if !ok {
+		return nil, errors.NotFoundf("page %s", slug)
+	}
+	copied := *page
+	return &copied, nil
+}
+
+func (m *Memory) CreatePage(page *Page) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.pages[page.Slug]; ok {
+		return errors.AlreadyExistsf("page %s", page.Slug)
+	}
+	m.lastID++
+	page.ID = m.lastID
+	copied := *page
+	m.pages[page.Slug] = &copied
+	return nil
+}
+
+func (m *Memory) UpdatePage(page *Page) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	existing, ok := m.pages[page.Slug]
+	if !ok {
+		return errors.NotFoundf("page %s", page.Slug)
+	}
+	existing.Content = page.Content
+	existing.Excerpt = page.Excerpt
+	existing.Template = page.Template
+	if page.Status != "" {
+		existing.Status = page.Status
+		existing.Date = page.Date
+	}
+	return nil
+}
+
+func (m *Memory) DeletePage(slug string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.pages[slug]; !ok {
+		return errors.NotFoundf("page %s", slug)
+	}
+	delete(m.pages, slug)
+	return nil
+}
+
+func (m *Memory) ListPages(parent int) ([]*Page, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	pages := make([]*Page, 0)
+	for _, page := range m.pages {
+		if page.Parent == parent {
+			copied := *page
+			pages = append(pages, &copied)
+		}
+	}
+	sort.Slice(pages, func(i, j int) bool { return pages[i].ID < pages[j].ID })
+	return pages, nil
+}
+
+func (m *Memory) ApplySEO(slug string, s *seo.SEO) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	page, ok := m.pages[slug]
+	if !ok {
+		return errors.Annotate(errors.NotFoundf("page %s", slug), "cannot load page")
+	}
+	page.SEO = s
+	return nil
+}
+
+func (m *Memory) MediaExists(path string) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	_, ok := m.media[path]
+	return ok, nil
+}
+
+func (m *Memory) UploadMedia(path string, data []byte) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.media[path] = data
+	return nil
+}
@@ -0,0 +1,191 @@
+package publish
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/raid-codex/tools/common/paged"
+	"github.com/raid-codex/tools/seo"
+	"github.com/raid-codex/tools/utils/minify"
+)
+
+// Page is a page as stored by a publisher
+type Page struct {
+	// ID is set by the publisher, 0 for publishers without ids
+	ID       int      `json:"id"`
+	Slug     string   `json:"slug"`
+	Title    string   `json:"title"`
+	Template string   `json:"template"`
+	Status   string   `json:"status"`
+	Parent   int      `json:"parent"`
+	Content  string   `json:"content"`
+	Excerpt  string   `json:"excerpt"`
+	SEO      *seo.SEO `json:"seo,omitempty"`
+	// Date is when a page with the future status goes live
+	Date *time.Time `json:"date,omitempty"`
+}
+
+const (
+	Status_Private = "private"
+	Status_Publish = "publish"
+	Status_Draft   = "draft"
+	Status_Future  = "future"
+)
+
+// Publisher stores the pages of the website
+type Publisher interface {
+	// GetPage returns the page with the slug, or a NotFound error
+	GetPage(slug string) (*Page, error)
+	// CreatePage stores a new page, or returns an AlreadyExists error
+	CreatePage(page *Page) error
+	// UpdatePage replaces the content, excerpt and template of an existing page, and its status and
+	// date when the status is set
+	UpdatePage(page *Page) error
+	// DeletePage removes the page with the slug, or returns a NotFound error
+	DeletePage(slug string) error
+	// ListPages returns every page whose parent is the page with the id
+	ListPages(parent int) ([]*Page, error)
+	// ApplySEO sets the SEO metadata of the page with the slug
+	ApplySEO(slug string, s *seo.SEO) error
+	// MediaExists tells whether the media at path, relative to the uploads, is served
+	MediaExists(path string) (bool, error)
+	// UploadMedia stores data as the media at path, relative to the uploads
+	UploadMedia(path string, data []byte) error
+}
+
+const (
+	Backend_WordPress  = "wordpress"
+	Backend_Filesystem = "filesystem"
+	Backend_Memory     = "memory"
+)
+
+// FromEnv returns the publisher selected by PUBLISHER (wordpress by default):
+//   - wordpress uses WP_BASE_URL (https://raid-codex.com by default), WP_USER and WP_PASSWORD
+//   - filesystem writes to PUBLISH_DIRECTORY
+//   - memory keeps pages until the process exits
+func FromEnv() (Publisher, error) {
+	backend, location, errTarget := targetFromEnv()
+	if errTarget != nil {
+		return nil, errTarget
+	}
+	switch backend {
+	case Backend_WordPress:
+		return NewWordPress(location, os.Getenv("WP_USER"), os.Getenv("WP_PASSWORD")), nil
+	case Backend_Filesystem:
+		return NewFilesystem(location), nil
+	default:
+		return NewMemory(), nil
+	}
+}
+
+// targetFromEnv returns the backend selected by PUBLISHER and where it stores the pages: the site
+// URL for wordpress, the directory for filesystem and nothing for memory
+func targetFromEnv() (string, string, error) {
+	switch backend := os.Getenv("PUBLISHER"); backend {
+	case "", Backend_WordPress:
+		baseURL := os.Getenv("WP_BASE_URL")
+		if baseURL == "" {
+			baseURL = DefaultWordPressURL
+		}
+		return Backend_WordPress, strings.TrimSuffix(baseURL, "/"), nil
+	case Backend_Filesystem:
+		directory := os.Getenv("PUBLISH_DIRECTORY")
+		if directory == "" {
+			return "", "", fmt.Errorf("PUBLISH_DIRECTORY is required by the %s publisher", Backend_Filesystem)
+		}
+		return Backend_Filesystem, directory, nil
+	case Backend_Memory:
+		return Backend_Memory, "", nil
+	default:
+		return "", "", errors.NotSupportedf("publisher %s", backend)
+	}
+}
+
+// PageOf returns the private page of an entity with the given content
+func PageOf(p paged.Paged, content string) *Page {
+	return &Page{
+		Slug:     p.LinkName(),
+		Title:    p.GetPageTitle(),
+		Template: p.GetPageTemplate(),
+		Status:   Status_Private,
+		Parent:   p.GetParentPageID(),
+		Content:  content,
+		Excerpt:  p.GetPageExcerpt(),
+	}
+}
+
+// Publish creates the page of an entity with the status of schedule, or updates it when it already
+// exists. Pages whose hash matches the one in hashes are left untouched, every page is pushed when
+// hashes is nil.
+func Publish(publisher Publisher, hashes *HashStore, p paged.Paged, content string, schedule Schedule) (Result, error) {
+	slug := p.GetPageSlug()
+	existing, errPage := publisher.GetPage(slug)
+	if errPage != nil && !errors.IsNotFound(errPage) {
+		return "", errPage
+	}
+	page := PageOf(p, content)
+	page.Status = schedule.Status
+	page.Date = schedule.Date
+	if errPage == nil && existing.Status == Status_Publish && page.Status == Status_Future {
+		// pages already live are not taken down until their scheduled date
+		page.Status, page.Date = "", nil
+	}
+	hash := Hash(page)
+	if errPage != nil {
+		if page.Status == "" {
+			page.Status = Status_Private
+		}
+		if errCreate := publisher.CreatePage(page); errCreate != nil {
+			return "", errors.Annotatef(errCreate, "error while creating page")
+		}
+		if hashes != nil {
+			hashes.Set(slug, hash)
+		}
+		return Result_Created, nil
+	}
+	if hashes != nil && hashes.Get(slug) == hash {
+		return Result_Unchanged, nil
+	}
+	page.ID = existing.ID
+	page.Slug = existing.Slug
+	if errUpdate := publisher.UpdatePage(page); errUpdate != nil {
+		return "", errors.Annotatef(errUpdate, "error while updating page")
+	}
+	if hashes != nil {
+		hashes.Set(slug, hash)
+	}
+	return Result_Updated, nil
+}
+
+// Content renders the page of an entity with tmpl and minifies it, or reads the template from
+// templateFile when tmpl is nil. It is empty when templateFile is empty.
+func Content(p paged.Paged, templateFile, dataDirectory string, tmpl *template.Template) (string, error) {
+	if templateFile == "" {
+		return "", nil
+	}
+	data, errData := p.GetPageExtraData(dataDirectory)
+	if errData != nil {
+		return "", errData
+	}
+	buf := bytes.NewBufferString("")
+	var errTemplate error
+	if tmpl != nil {
+		errTemplate = p.GetPageContent_Templates(tmpl, buf, data)
+	} else {
+		inputFile, errInput := os.Open(templateFile)
+		if errInput != nil {
+			return "", errInput
+		}
+		defer inputFile.Close()
+		errTemplate = p.GetPageContent(inputFile, buf, data)
+	}
+	if errTemplate != nil {
+		return "", errTemplate
+	}
+	return minify.HTML(buf.String())
+}
@@ -0,0 +1,116 @@
+package publish
+
+import (
+	"log"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/raid-codex/tools/seo"
+)
+
+// Retry retries the calls to a publisher failing with a network error, or because the server is
+// rate limiting or failing, waiting Backoff after the first failure and twice as long after each of
+// the next ones. Other errors would fail again and are returned at once.
+type Retry struct {
+	Publisher Publisher
+	Attempts  int
+	Backoff   time.Duration
+}
+
+// WithRetry returns publisher retrying every call up to attempts times
+func WithRetry(publisher Publisher, attempts int, backoff time.Duration) *Retry {
+	return &Retry{Publisher: publisher, Attempts: attempts, Backoff: backoff}
+}
+
+func (r *Retry) do(name string, call func() error) error {
+	wait := r.Backoff
+	var err error
+	for attempt := 1; ; attempt++ {
+		err = call()
+		if err == nil || !retryable(err) || attempt >= r.Attempts {
+			return err
+		}
+		log.Printf("%s failed (attempt %d/%d), retrying in %s: %v\n", name, attempt, r.Attempts, wait, err)
+		time.Sleep(wait)
+		wait *= 2
+	}
+}
+
+// StatusError is returned by publishers talking HTTP when the server answers with an error status
+type StatusError struct {
+	StatusCode int
+	Status     string
+}
+
+func (e *StatusError) Error() string { return e.Status }
+
+// retryable tells whether a failed call may succeed when made again
+func retryable(err error) bool {
+	switch cause := errors.Cause(err).(type) {
+	case *StatusError:
+		return cause.StatusCode == http.StatusTooManyRequests || cause.StatusCode >= http.StatusInternalServerError
+	case net.Error:
+		return true
+	}
+	return false
+}
+
+func (r *Retry) GetPage(slug string) (*Page, error) {
+	var page *Page
+	err := r.do("get "+slug, func() (err error) {
+		page, err = r.Publisher.GetPage(slug)
+		return err
+	})
+	return page, err
+}
+
+// CreatePage checks the page is still missing before retrying, a failed call may have created it
+func (r *Retry) CreatePage(page *Page) error {
+	retried := false
+	return r.do("create "+page.Slug, func() error {
+		if retried {
+			if existing, errPage := r.Publisher.GetPage(page.Slug); errPage == nil {
+				page.ID = existing.ID
+				return nil
+			}
+		}
+		retried = true
+		return r.Publisher.CreatePage(page)
+	})
+}
+
+func (r *Retry) UpdatePage(page *Page) error {
+	return r.do("update "+page.Slug, func() error { return r.Publisher.UpdatePage(page) })
+}
+
+func (r *Retry) DeletePage(slug string) error {
+	return r.do("delete "+slug, func() error { return r.Publisher.DeletePage(slug) })
+}
+
+func (r *Retry) ListPages(parent int) ([]*Page, error) {
+	var pages []*Page
+	err := r.do("list", func() (err error) {
+		pages, err = r.Publisher.ListPages(parent)
+		return err
+	})
+	return pages, err
+}
+
+func (r *Retry) ApplySEO(slug string, s *seo.SEO) error {
+	return r.do("apply seo to "+slug, func() error { return r.Publisher.ApplySEO(slug, s) })
+}
+
+func (r *Retry) MediaExists(path string) (bool, error) {
+	var exists bool
+	err := r.do("check "+path, func() (err error) {
+		exists, err = r.Publisher.MediaExists(path)
+		return err
+	})
+	return exists, err
+}
+
+func (r *Retry) UploadMedia(path string, data []byte) error {
+	return r.do("upload "+path, func() error { return r.Publisher.UploadMedia(path, data) })
+}
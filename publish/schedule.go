@@ -0,0 +1,50 @@
+package publish
+
+import (
+	"fmt"
+	"time"
+)
+
+// Schedule is the status pages are published with. The zero value creates private pages and leaves
+// the status of existing pages untouched.
+type Schedule struct {
+	Status string
+	// Date is when a page with the future status goes live
+	Date *time.Time
+}
+
+// Scheduled returns the schedule of a page going live at date
+func Scheduled(date time.Time) Schedule {
+	return Schedule{Status: Status_Future, Date: &date}
+}
+
+// ParseSchedule reads the status and the RFC 3339 date pages go live at, both optional. Pages are
+// scheduled when a date is given.
+func ParseSchedule(status, date string) (Schedule, error) {
+	switch status {
+	case "", Status_Private, Status_Draft, Status_Publish, Status_Future:
+	default:
+		return Schedule{}, fmt.Errorf("unknown status %s, expected one of %s, %s, %s or %s", status, Status_Private, Status_Draft, Status_Publish, Status_Future)
+	}
+	if date == "" {
+		if status == Status_Future {
+			return Schedule{}, fmt.Errorf("a date is required to schedule pages")
+		}
+		return Schedule{Status: status}, nil
+	}
+	if status != "" && status != Status_Future {
+		return Schedule{}, fmt.Errorf("pages with a date are scheduled, status %s cannot be used", status)
+	}
+	t, errParse := time.Parse(time.RFC3339, date)
+	if errParse != nil {
+		return Schedule{}, errParse
+	}
+	return Scheduled(t), nil
+}
+
+func (s Schedule) String() string {
+	if s.Date != nil {
+		return fmt.Sprintf("%s at %s", s.Status, s.Date.Format(time.RFC3339))
+	}
+	return s.Status
+}
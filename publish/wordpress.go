@@ -0,0 +1,301 @@
+package publish
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/raid-codex/tools/seo"
+	"github.com/sogko/go-wordpress"
+)
+
+// DefaultWordPressURL is the website the pages are published to
+const DefaultWordPressURL = "https://raid-codex.com"
+
+// WordPress publishes pages through the REST API of a WordPress site, SEO metadata are Yoast fields
+type WordPress struct {
+	baseURL  string
+	username string
+	password string
+}
+
+// NewWordPress returns a publisher for the WordPress site at baseURL (e.g. https://raid-codex.com)
+func NewWordPress(baseURL, username, password string) *WordPress {
+	return &WordPress{
+		baseURL:  strings.TrimSuffix(baseURL, "/"),
+		username: username,
+		password: password,
+	}
+}
+
+// client returns a new API client, clients hold the state of their request and cannot be shared
+// between goroutines
+func (wp *WordPress) client() *wordpress.Client {
+	return wordpress.NewClient(&wordpress.Options{
+		BaseAPIURL: fmt.Sprintf("%s/wp-json/wp/v2", wp.baseURL),
+		Username:   wp.username,
+		Password:   wp.password,
+	})
+}
+
+func (wp *WordPress) getPage(slug string) (*wordpress.Page, error) {
+	pages, resp, body, err := wp.client().Pages().List(map[string]string{
+		"slug":   slug,
+		"status": strings.Join([]string{Status_Private, Status_Publish, Status_Draft, Status_Future}, ","),
+	})
+	if err != nil {
+		return nil, wordpressError(resp, body, err)
+	} else if len(pages) == 0 {
+		return nil, errors.NotFoundf("page %s", slug)
+	}
+	return &pages[0], nil
+}
+
+func (wp *WordPress) GetPage(slug string) (*Page, error) {
+	page, errPage := wp.getPage(slug)
+	if errPage != nil {
+		return nil, errPage
+	}
+	return fromWordPress(page), nil
+}
+
+// ListPages returns the pages under parent, walking every page of results
+func (wp *WordPress) ListPages(parent int) ([]*Page, error) {
+	list := make([]*Page, 0)
+	for n := 1; ; n++ {
+		pages, resp, body, err := wp.client().Pages().List(map[string]string{
+			"parent":   strconv.Itoa(parent),
+			"status":   strings.Join([]string{Status_Private, Status_Publish, Status_Draft, Status_Future}, ","),
+			"per_page": strconv.Itoa(listPageSize),
+			"page":     strconv.Itoa(n),
+		})
+		if err != nil {
+			return nil, wordpressError(resp, body, err)
+		}
+		for idx := range pages {
+			list = append(list, fromWordPress(&pages[idx]))
+		}
+		// the total is only missing when the site strips the header, short pages are the last ones then
+		if total, errTotal := strconv.Atoi(resp.Header.Get("X-WP-TotalPages")); errTotal == nil {
+			if n >= total {
+				return list, nil
+			}
+		} else if len(pages) < listPageSize {
+			return list, nil
+		}
+	}
+}
+
+// listPageSize is the largest page of results the API accepts
+const listPageSize = 100
+
+func fromWordPress(page *wordpress.Page) *Page {
+	p := &Page{
+		ID:       page.ID,
+		Slug:     page.Slug,
+		Title:    page.Title.Raw,
+		Template: page.Template,
+		Status:   page.Status,
+		Parent:   page.Parent,
+		Content:  page.Content.Raw,
+		Excerpt:  page.Excerpt.Raw,
+	}
+	if page.Status == Status_Future {
+		if date, errParse := time.Parse(wordpressDateLayout, page.DateGMT); errParse == nil {
+			p.Date = &date
+		}
+	}
+	return p
+}
+
+// wordpressDateLayout is the layout of the dates of the API, date_gmt is in UTC
+const wordpressDateLayout = "2006-01-02T15:04:05"
+
+func dateGMT(date *time.Time) string {
+	if date == nil {
+		return ""
+	}
+	return date.UTC().Format(wordpressDateLayout)
+}
+
+func (wp *WordPress) CreatePage(page *Page) error {
+	created, resp, body, err := wp.client().Pages().Create(&wordpress.Page{
+		Slug:     page.Slug,
+		Title:    wordpress.Title{Raw: page.Title},
+		Type:     "page",
+		Template: page.Template,
+		Status:   page.Status,
+		DateGMT:  dateGMT(page.Date),
+		Parent:   page.Parent,
+		Content:  wordpress.Content{Raw: page.Content},
+		Excerpt:  wordpress.Excerpt{Raw: page.Excerpt},
+	})
+	if err != nil {
+		return wordpressError(resp, body, err)
+	}
+	page.ID = created.ID
+	return nil
+}
+
+func (wp *WordPress) UpdatePage(page *Page) error {
+	id := page.ID
+	if id == 0 {
+		existing, errPage := wp.getPage(page.Slug)
+		if errPage != nil {
+			return errPage
+		}
+		id = existing.ID
+	}
+	_, resp, body, err := wp.client().Pages().Update(id, &wordpress.Page{
+		Content:  wordpress.Content{Raw: page.Content},
+		Excerpt:  wordpress.Excerpt{Raw: page.Excerpt},
+		Template: page.Template,
+		// empty fields are omitted, the status is untouched when not set
+		Status:  page.Status,
+		DateGMT: dateGMT(page.Date),
+	})
+	if err != nil {
+		return wordpressError(resp, body, err)
+	}
+	return nil
+}
+
+func (wp *WordPress) DeletePage(slug string) error {
+	page, errPage := wp.getPage(slug)
+	if errPage != nil {
+		return errPage
+	}
+	_, resp, body, err := wp.client().Pages().Delete(page.ID, map[string]string{"force": "true"})
+	if err != nil {
+		return wordpressError(resp, body, err)
+	}
+	return nil
+}
+
+func (wp *WordPress) ApplySEO(slug string, s *seo.SEO) error {
+	payload := yoastPayload{
+		YoastTitle:           s.Title,
+		YoastMetaDescription: s.Description,
+		YoastFocusKeywords:   strings.Join(s.Keywords, " "),
+		YoastOGDescription:   s.Description,
+		YoastOGTitle:         s.Title,
+	}
+	page, errPage := wp.getPage(slug)
+	if errPage != nil {
+		return errors.Annotate(errPage, "cannot load page")
+	}
+	var current yoastPayload
+	pageURL := fmt.Sprintf("%s/wp-json/wp/v2/pages/%d", wp.baseURL, page.ID)
+	client := wp.client()
+	resp, body, errGetCurrentSEOTags := client.Get(pageURL, nil, &current)
+	if errGetCurrentSEOTags != nil {
+		return errors.Annotate(wordpressError(resp, body, errGetCurrentSEOTags), "cannot fetch current SEO tags")
+	}
+	diff := current.Diff(payload)
+	if diff != nil {
+		for k, v := range diff {
+			fmt.Printf("Diff with field %s:\n%s\n", k, v)
+		}
+	} else {
+		// no diff, don't call API
+		fmt.Printf("no diff, skipping\n")
+		return nil
+	}
+	res := map[string]interface{}{}
+	resp, body, errUpdate := client.Update(pageURL, payload, &res)
+	if errUpdate != nil {
+		return errors.Annotate(wordpressError(resp, body, errUpdate), "cannot update SEO tags")
+	}
+	return nil
+}
+
+// MediaExists checks the media is served under wp-content/uploads, where the templates expect it
+func (wp *WordPress) MediaExists(path string) (bool, error) {
+	resp, errHead := http.Head(fmt.Sprintf("%s/wp-content/uploads/%s", wp.baseURL, path))
+	if errHead != nil {
+		return false, errHead
+	}
+	resp.Body.Close()
+	switch {
+	case resp.StatusCode == http.StatusOK:
+		return true, nil
+	case resp.StatusCode == http.StatusNotFound:
+		return false, nil
+	default:
+		return false, errors.Annotatef(&StatusError{StatusCode: resp.StatusCode, Status: resp.Status}, "unexpected status for media %s", path)
+	}
+}
+
+// UploadMedia uploads through the endpoint of the raid-codex plugin, which stores the file at path
+// under wp-content/uploads. The media API would file it under the folder of the month instead. The
+// plugin only accepts images under the folders of the templates and never replaces an existing file.
+func (wp *WordPress) UploadMedia(path string, data []byte) error {
+	contentType := mime.TypeByExtension(filepath.Ext(path))
+	if contentType == "" {
+		contentType = http.DetectContentType(data)
+	}
+	req, errReq := http.NewRequest(http.MethodPost, fmt.Sprintf("%s/wp-json/raid-codex/v1/uploads?path=%s", wp.baseURL, url.QueryEscape(path)), bytes.NewReader(data))
+	if errReq != nil {
+		return errReq
+	}
+	req.SetBasicAuth(wp.username, wp.password)
+	req.Header.Set("Content-Type", contentType)
+	resp, errUpload := http.DefaultClient.Do(req)
+	if errUpload != nil {
+		return errors.Annotatef(errUpload, "cannot upload %s", path)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= http.StatusBadRequest {
+		body, _ := ioutil.ReadAll(resp.Body)
+		return errors.Annotatef(wordpressError(resp, body, nil), "cannot upload %s", path)
+	}
+	return nil
+}
+
+type yoastPayload struct {
+	YoastTitle           string `json:"_yoast_wpseo_title,omitempty"`
+	YoastMetaDescription string `json:"_yoast_wpseo_metadesc,omitempty"`
+	YoastFocusKeywords   string `json:"_yoast_wpseo_focuskw,omitempty"`
+	YoastOGDescription   string `json:"_yoast_wpseo_opengraph-description,omitempty"`
+	YoastOGTitle         string `json:"_yoast_wpseo_opengraph-title,omitempty"`
+}
+
+func (a yoastPayload) Diff(b yoastPayload) map[string]string {
+	diff := map[string]string{}
+	t := reflect.TypeOf(a)
+	av := reflect.ValueOf(a)
+	bv := reflect.ValueOf(b)
+	for i := 0; i < t.NumField(); i++ {
+		if av.Field(i).String() != bv.Field(i).String() {
+			diff[t.Field(i).Name] = fmt.Sprintf("\tcurrent: %s\n\tapplied: %s", av.Field(i).String(), bv.Field(i).String())
+		}
+	}
+	if len(diff) == 0 {
+		return nil
+	}
+	return diff
+}
+
+func logWordpressError(body []byte) {
+	fmt.Fprintf(os.Stderr, "%s\n", body)
+}
+
+// wordpressError logs the body of a failed call and returns its error, a StatusError when the API
+// answered with an error status
+func wordpressError(resp *http.Response, body []byte, err error) error {
+	logWordpressError(body)
+	if resp != nil && resp.StatusCode >= http.StatusBadRequest {
+		return &StatusError{StatusCode: resp.StatusCode, Status: resp.Status}
+	}
+	return err
+}
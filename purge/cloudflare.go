@@ -0,0 +1,75 @@
+package purge
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/cloudflare/cloudflare-go"
+)
+
+// MaxItems is the maximum number of files or tags Cloudflare purges in a request
+const MaxItems = 30
+
+// Cloudflare purges the cache of a zone
+type Cloudflare struct {
+	API    *cloudflare.API
+	ZoneID string
+}
+
+// CloudflareFromEnv returns the purger configured by CF_API_KEY, CF_API_EMAIL and CF_ZONE_ID.
+// CF_API_URL replaces the base url of the API, e.g. to point to a stub.
+func CloudflareFromEnv() (*Cloudflare, error) {
+	api, err := cloudflare.New(os.Getenv("CF_API_KEY"), os.Getenv("CF_API_EMAIL"))
+	if err != nil {
+		return nil, err
+	}
+	if baseURL := os.Getenv("CF_API_URL"); baseURL != "" {
+		api.BaseURL = baseURL
+	}
+	return &Cloudflare{API: api, ZoneID: os.Getenv("CF_ZONE_ID")}, nil
+}
+
+// Everything purges the whole zone
+func (c *Cloudflare) Everything(ctx context.Context) error {
+	return c.purge(ctx, cloudflare.PurgeCacheRequest{Everything: true})
+}
+
+// Files purges the urls, in batches of MaxItems
+func (c *Cloudflare) Files(ctx context.Context, urls []string) error {
+	return batch(urls, func(items []string) error {
+		return c.purge(ctx, cloudflare.PurgeCacheRequest{Files: items})
+	})
+}
+
+// Tags purges the pages carrying the tags, in batches of MaxItems
+func (c *Cloudflare) Tags(ctx context.Context, tags []string) error {
+	return batch(tags, func(items []string) error {
+		return c.purge(ctx, cloudflare.PurgeCacheRequest{Tags: items})
+	})
+}
+
+func (c *Cloudflare) purge(ctx context.Context, req cloudflare.PurgeCacheRequest) error {
+	resp, err := c.API.PurgeCache(ctx, c.ZoneID, req)
+	if err != nil {
+		return err
+	} else if !resp.Success {
+		return fmt.Errorf("could not purge cache: %+v", resp.Errors)
+	}
+	return nil
+}
+
+func batch(items []string, fn func([]string) error) error {
+	for start := 0; start < len(items); start += MaxItems {
+		end := start + MaxItems
+		if end > len(items) {
+			end = len(items)
+		}
+		log.Printf("purging %d to %d of %d\n", start+1, end, len(items))
+		if err := fn(items[start:end]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,117 @@
+package purge
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/cloudflare/cloudflare-go"
+)
+
+// stubCloudflare serves the purge endpoint of the zone through CF_API_URL and records the requests
+type stubCloudflare struct {
+	mu       sync.Mutex
+	requests []cloudflare.PurgeCacheRequest
+	// fail answers the requests with an unsuccessful response
+	fail bool
+}
+
+func newStubCloudflare(t *testing.T) (*stubCloudflare, *Cloudflare) {
+	stub := &stubCloudflare{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/zones/zone-id/purge_cache" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			http.NotFound(w, r)
+			return
+		}
+		var req cloudflare.PurgeCacheRequest
+		if errJSON := json.NewDecoder(r.Body).Decode(&req); errJSON != nil {
+			t.Errorf("cannot read purge request: %s", errJSON)
+		}
+		stub.mu.Lock()
+		stub.requests = append(stub.requests, req)
+		fail := stub.fail
+		stub.mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		if fail {
+			fmt.Fprint(w, `{"success":false,"errors":[{"code":1234,"message":"zone not found"}],"messages":[],"result":null}`)
+			return
+		}
+		fmt.Fprint(w, `{"success":true,"errors":[],"messages":[],"result":{"id":"zone-id"}}`)
+	}))
+	t.Cleanup(server.Close)
+	t.Setenv("CF_API_KEY", "key")
+	t.Setenv("CF_API_EMAIL", "user@example.com")
+	t.Setenv("CF_ZONE_ID", "zone-id")
+	t.Setenv("CF_API_URL", server.URL)
+	cf, errCloudflare := CloudflareFromEnv()
+	if errCloudflare != nil {
+		t.Fatal(errCloudflare)
+	}
+	return stub, cf
+}
+
+func TestCloudflareEverything(t *testing.T) {
+	stub, cf := newStubCloudflare(t)
+	if err := cf.Everything(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if len(stub.requests) != 1 || !stub.requests[0].Everything {
+		t.Errorf("expected a request purging everything, got %+v", stub.requests)
+	}
+}
+
+func TestCloudflareFilesInBatches(t *testing.T) {
+	stub, cf := newStubCloudflare(t)
+	urls := make([]string, 2*MaxItems+5)
+	for idx := range urls {
+		urls[idx] = fmt.Sprintf("https://raid-codex.com/champions/champion-%d/", idx)
+	}
+	if err := cf.Files(context.Background(), urls); err != nil {
+		t.Fatal(err)
+	}
+	if len(stub.requests) != 3 {
+		t.Fatalf("expected 3 requests, got %d", len(stub.requests))
+	}
+	purged := make([]string, 0, len(urls))
+	for idx, req := range stub.requests {
+		if len(req.Files) > MaxItems {
+			t.Errorf("request %d purges %d files, more than %d", idx, len(req.Files), MaxItems)
+		}
+		purged = append(purged, req.Files...)
+	}
+	if !reflect.DeepEqual(purged, urls) {
+		t.Errorf("expected every url to be purged once, in order, got %v", purged)
+	}
+}
+
+func TestCloudflareTags(t *testing.T) {
+	stub, cf := newStubCloudflare(t)
+	tags := []string{"champions-kael", "factions-dark-elves"}
+	if err := cf.Tags(context.Background(), tags); err != nil {
+		t.Fatal(err)
+	}
+	if len(stub.requests) != 1 || !reflect.DeepEqual(stub.requests[0].Tags, tags) {
+		t.Errorf("expected a request purging %v, got %+v", tags, stub.requests)
+	}
+}
+
+func TestCloudflareFailure(t *testing.T) {
+	stub, cf := newStubCloudflare(t)
+	stub.fail = true
+	urls := make([]string, MaxItems+1)
+	for idx := range urls {
+		urls[idx] = fmt.Sprintf("https://raid-codex.com/effects/effect-%d", idx)
+	}
+	if err := cf.Files(context.Background(), urls); err == nil {
+		t.Fatal("expected an error when Cloudflare does not purge")
+	}
+	if len(stub.requests) != 1 {
+		t.Errorf("expected the batches to stop at the first failure, got %d requests", len(stub.requests))
+	}
+}
@@ -0,0 +1,65 @@
+package purge
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/raid-codex/tools/common"
+)
+
+// ChangesSince returns the changes of the data files of dataDirectory, a git repository, since the
+// revision. The champions carry their version at the revision, so the pages a renamed or deleted
+// champion was displayed on are purged.
+func ChangesSince(dataDirectory, revision string) ([]Change, error) {
+	output, errDiff := git(dataDirectory, "diff", "--name-only", "--relative", revision, "--", "docs")
+	if errDiff != nil {
+		return nil, errDiff
+	}
+	changes := make([]Change, 0)
+	for _, file := range strings.Split(string(output), "\n") {
+		change, ok := ChangeOfFile(file)
+		if !ok {
+			continue
+		}
+		if change.Kind == Kind_Champion {
+			previous, errPrevious := championAt(dataDirectory, revision, file)
+			if errPrevious != nil {
+				return nil, errPrevious
+			}
+			change.Previous = previous
+		}
+		changes = append(changes, change)
+	}
+	return changes, nil
+}
+
+// championAt returns the champion of the file at the revision, nil when the file did not exist
+func championAt(dataDirectory, revision, file string) (*common.Champion, error) {
+	if _, errExists := git(dataDirectory, "cat-file", "-e", fmt.Sprintf("%s:./%s", revision, file)); errExists != nil {
+		return nil, nil
+	}
+	data, errShow := git(dataDirectory, "show", fmt.Sprintf("%s:./%s", revision, file))
+	if errShow != nil {
+		return nil, errShow
+	}
+	var champion common.Champion
+	if errJSON := json.Unmarshal(data, &champion); errJSON != nil {
+		return nil, errors.Annotatef(errJSON, "cannot read %s at %s", file, revision)
+	}
+	return &champion, nil
+}
+
+func git(directory string, args ...string) ([]byte, error) {
+	cmd := exec.Command("git", append([]string{"-C", directory}, args...)...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	output, errGit := cmd.Output()
+	if errGit != nil {
+		return nil, fmt.Errorf("git %s: %s %s", args[0], errGit, strings.TrimSpace(stderr.String()))
+	}
+	return output, nil
+}
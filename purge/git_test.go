@@ -0,0 +1,65 @@
+package purge
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+func TestChangesSince(t *testing.T) {
+	if _, errGit := exec.LookPath("git"); errGit != nil {
+		t.Skip("git is not installed")
+	}
+	directory := writeTestData(t, testChampions)
+	champions := filepath.Join(directory, "docs", "champions", "current")
+	write := func(name, content string) {
+		if errWrite := os.WriteFile(filepath.Join(champions, name), []byte(content), 0644); errWrite != nil {
+			t.Fatal(errWrite)
+		}
+	}
+	run := func(args ...string) {
+		args = append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)
+		if _, errGit := git(directory, args...); errGit != nil {
+			t.Fatal(errGit)
+		}
+	}
+	write("kael.json", `{"name": "Kael", "slug": "kael", "faction_slug": "dark-elves"}`)
+	write("bar.json", `{"name": "Bar", "slug": "bar", "faction_slug": "banner-lords"}`)
+	run("init", "-q")
+	run("add", "-A")
+	run("commit", "-q", "-m", "data")
+	write("kael.json", `{"name": "Kael", "slug": "kael", "faction_slug": "banner-lords"}`)
+	write("foo.json", `{"name": "Foo", "slug": "foo", "faction_slug": "banner-lords"}`)
+	if errRemove := os.Remove(filepath.Join(champions, "bar.json")); errRemove != nil {
+		t.Fatal(errRemove)
+	}
+	write("index.json", `[]`)
+	run("add", "-A")
+	run("commit", "-q", "-m", "changes")
+
+	changes, errChanges := ChangesSince(directory, "HEAD~1")
+	if errChanges != nil {
+		t.Fatal(errChanges)
+	}
+	previous := map[string]string{}
+	for _, change := range changes {
+		if change.Kind != Kind_Champion {
+			t.Errorf("unexpected change %+v", change)
+			continue
+		}
+		previous[change.Slug] = ""
+		if change.Previous != nil {
+			previous[change.Slug] = change.Previous.FactionSlug
+		}
+	}
+	expected := map[string]string{"bar": "banner-lords", "foo": "", "kael": "dark-elves"}
+	if len(previous) != len(expected) {
+		t.Fatalf("expected changes of %v, got %v", expected, previous)
+	}
+	for slug, faction := range expected {
+		if actual, ok := previous[slug]; !ok || actual != faction {
+			t.Errorf("%s: expected the previous faction %q, got %q", slug, faction, actual)
+		}
+	}
+}
@@ -0,0 +1,191 @@
+package purge
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/raid-codex/tools/common"
+)
+
+// Kinds of entities, named after their folder in docs/
+const (
+	Kind_Champion     = "champions"
+	Kind_Faction      = "factions"
+	Kind_StatusEffect = "status-effects"
+	Kind_Fusion       = "fusions"
+)
+
+// Change is an entity whose data changed
+type Change struct {
+	Kind string
+	Slug string
+	// Previous is the champion before the change, nil when unknown or when the champion is new. The
+	// pages it was displayed on are purged too, as the champion may no longer be on them.
+	Previous *common.Champion
+}
+
+// Tag is the cache tag of the pages of the entity, for origins sending Cache-Tag headers
+func (c Change) Tag() string {
+	return fmt.Sprintf("%s-%s", c.Kind, c.Slug)
+}
+
+// ChangeOfFile returns the change of a data file, e.g. docs/champions/current/kael.json. Index
+// files are rewritten with the files of the entities and tell nothing about what changed, they are
+// ignored like the files outside of the current data.
+func ChangeOfFile(file string) (Change, bool) {
+	parts := strings.Split(filepath.ToSlash(file), "/")
+	if len(parts) < 3 || parts[len(parts)-2] != "current" {
+		return Change{}, false
+	}
+	name := parts[len(parts)-1]
+	if filepath.Ext(name) != ".json" || name == "index.json" {
+		return Change{}, false
+	}
+	switch kind := parts[len(parts)-3]; kind {
+	case Kind_Champion, Kind_Faction, Kind_StatusEffect, Kind_Fusion:
+		return Change{Kind: kind, Slug: strings.TrimSuffix(name, ".json")}, true
+	}
+	return Change{}, false
+}
+
+// Pages returns the links of the pages showing the changed entities, sorted: the pages of the
+// entities, the lists of their section, and the pages of the entities displaying them, e.g. the
+// faction, status effect and fusion pages of a champion
+func Pages(changes []Change) ([]string, error) {
+	champions, errChampions := common.GetChampions()
+	if errChampions != nil {
+		return nil, errChampions
+	}
+	effects, errEffects := common.GetStatuseffects()
+	if errEffects != nil {
+		return nil, errEffects
+	}
+	fusions, errFusions := common.GetFusions()
+	if errFusions != nil {
+		return nil, errFusions
+	}
+	fusionsBySlug := map[string]*common.Fusion{}
+	for _, fusion := range fusions {
+		fusionsBySlug[fusion.Slug] = fusion
+	}
+	links := map[string]bool{}
+	add := func(link string) {
+		if link == "" {
+			return
+		}
+		if !strings.HasSuffix(link, "/") {
+			link += "/"
+		}
+		links[link] = true
+	}
+	// fusions of the same champion share the page of the root fusion
+	addFusion := func(slug string) {
+		fusion, ok := fusionsBySlug[slug]
+		if !ok {
+			add(common.Fusion{Slug: slug}.GetWebsiteLink())
+			return
+		}
+		for fusion.ParentFusionSlug != nil && fusionsBySlug[*fusion.ParentFusionSlug] != nil {
+			fusion = fusionsBySlug[*fusion.ParentFusionSlug]
+		}
+		add(fusion.GetWebsiteLink())
+	}
+	// the pages displaying the champion: its faction, its effects and its fusions
+	addChampion := func(champion *common.Champion) {
+		add(common.Faction{Slug: champion.FactionSlug}.GetWebsiteLink())
+		for _, skill := range champion.Skills {
+			for _, effect := range skill.Effects {
+				add(effect.GetWebsiteLink())
+			}
+			for _, upgrade := range skill.Upgrades {
+				for _, effect := range upgrade.Effects {
+					add(effect.GetWebsiteLink())
+				}
+			}
+		}
+		for _, fusion := range champion.FusionData {
+			addFusion(fusion.FusionSlug)
+		}
+	}
+	for _, change := range changes {
+		switch change.Kind {
+		case Kind_Champion:
+			add("/champions/")
+			add(common.Champion{Slug: change.Slug}.GetWebsiteLink())
+			if change.Previous != nil {
+				addChampion(change.Previous)
+			}
+			for _, champion := range champions {
+				if champion.Slug == change.Slug {
+					addChampion(champion)
+				}
+				for _, synergy := range champion.Synergies {
+					for _, slug := range synergy.Champions {
+						if slug == change.Slug {
+							add(champion.GetWebsiteLink())
+						}
+					}
+				}
+			}
+		case Kind_Faction:
+			add("/factions/")
+			add(common.Faction{Slug: change.Slug}.GetWebsiteLink())
+			for _, champion := range champions {
+				if champion.FactionSlug == change.Slug {
+					add(champion.GetWebsiteLink())
+				}
+			}
+		case Kind_StatusEffect:
+			add("/effects/")
+			link := common.StatusEffect{Slug: change.Slug}.GetWebsiteLink()
+			for _, effect := range effects {
+				if effect.Slug == change.Slug {
+					link = effect.GetWebsiteLink()
+				}
+			}
+			add(link)
+			for _, champion := range champions {
+				if common.FilterChampionStatusEffect(change.Slug)(champion) {
+					add(champion.GetWebsiteLink())
+				}
+			}
+		case Kind_Fusion:
+			add("/fusions/")
+			addFusion(change.Slug)
+			if fusion, ok := fusionsBySlug[change.Slug]; ok {
+				add(common.Champion{Slug: fusion.ChampionSlug}.GetWebsiteLink())
+				for _, ingredient := range fusion.Ingredients {
+					if ingredient.ChampionSlug != "" {
+						add(common.Champion{Slug: ingredient.ChampionSlug}.GetWebsiteLink())
+					}
+				}
+			}
+		default:
+			return nil, errors.NotSupportedf("kind %s of %s", change.Kind, change.Slug)
+		}
+	}
+	list := make([]string, 0, len(links))
+	for link := range links {
+		list = append(list, link)
+	}
+	sort.Strings(list)
+	return list, nil
+}
+
+// Tags returns the cache tags of the changed entities, sorted. Pages are expected to carry the tags
+// of every entity they display, so no dependent page is looked up.
+func Tags(changes []Change) []string {
+	tags := map[string]bool{}
+	for _, change := range changes {
+		tags[change.Tag()] = true
+	}
+	list := make([]string, 0, len(tags))
+	for tag := range tags {
+		list = append(list, tag)
+	}
+	sort.Strings(list)
+	return list
+}
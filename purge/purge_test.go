@@ -0,0 +1,140 @@
+package purge
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/raid-codex/tools/common"
+)
+
+// champions of the test data: kael, a dark elf poisoning his enemies and the ingredient of the foo
+// fusion, and foo, a banner lord with a synergy with kael
+const testChampions = `[
+	{"name": "Kael", "slug": "kael", "faction_slug": "dark-elves",
+	 "skills": [{"slug": "disintegrate", "effects": [{"slug": "poison", "type": "Poison"}]}],
+	 "fusion_data": [{"fusion_slug": "fusion-foo", "fusion_type": "ingredient"}]},
+	{"name": "Foo", "slug": "foo", "faction_slug": "banner-lords",
+	 "synergy": [{"champions": ["kael"]}]}
+]`
+
+// writeTestData writes the data directory of the test champions and returns it
+func writeTestData(t *testing.T, champions string) string {
+	directory := t.TempDir()
+	indexes := map[string]string{
+		"champions":      champions,
+		"factions":       `[{"name": "Dark Elves", "slug": "dark-elves"}, {"name": "Banner Lords", "slug": "banner-lords"}]`,
+		"status-effects": `[{"slug": "poison", "type": "Poison", "website_link": "/effects/poison"}]`,
+		"fusions":        `[{"name": "Foo", "slug": "fusion-foo", "champion_slug": "foo", "ingredients": [{"champion_slug": "kael"}]}]`,
+		"masteries":      `[]`,
+	}
+	for kind, index := range indexes {
+		folder := filepath.Join(directory, "docs", kind, "current")
+		if errMkdir := os.MkdirAll(folder, 0755); errMkdir != nil {
+			t.Fatal(errMkdir)
+		}
+		if errWrite := os.WriteFile(filepath.Join(folder, "index.json"), []byte(index), 0644); errWrite != nil {
+			t.Fatal(errWrite)
+		}
+	}
+	return directory
+}
+
+func TestChangeOfFile(t *testing.T) {
+	for file, expected := range map[string]*Change{
+		"docs/champions/current/kael.json":             {Kind: Kind_Champion, Slug: "kael"},
+		"data/docs/factions/current/dark-elves.json":   {Kind: Kind_Faction, Slug: "dark-elves"},
+		"docs/status-effects/current/poison.json":      {Kind: Kind_StatusEffect, Slug: "poison"},
+		"docs/fusions/current/fusion-foo.json":         {Kind: Kind_Fusion, Slug: "fusion-foo"},
+		"docs/champions/current/index.json":            nil,
+		"docs/champions/2019-05-01/kael.json":          nil,
+		"docs/masteries/current/deadly-precision.json": nil,
+		"README.md": nil,
+	} {
+		change, ok := ChangeOfFile(file)
+		if expected == nil {
+			if ok {
+				t.Errorf("%s: expected no change, got %+v", file, change)
+			}
+			continue
+		}
+		if !ok || change != *expected {
+			t.Errorf("%s: expected %+v, got %+v", file, *expected, change)
+		}
+	}
+}
+
+func TestPages(t *testing.T) {
+	if errFactory := common.InitFactory(writeTestData(t, testChampions)); errFactory != nil {
+		t.Fatal(errFactory)
+	}
+	for name, test := range map[string]struct {
+		changes  []Change
+		expected []string
+	}{
+		"champion": {
+			changes: []Change{{Kind: Kind_Champion, Slug: "kael"}},
+			expected: []string{
+				"/champions/", "/champions/foo/", "/champions/kael/", "/effects/poison/", "/factions/dark-elves/",
+				"/fusions/fusion-foo/",
+			},
+		},
+		"champion moved to another faction": {
+			changes: []Change{{
+				Kind: Kind_Champion, Slug: "foo",
+				Previous: &common.Champion{Slug: "foo", FactionSlug: "dark-elves"},
+			}},
+			expected: []string{"/champions/", "/champions/foo/", "/factions/banner-lords/", "/factions/dark-elves/"},
+		},
+		"deleted champion": {
+			changes: []Change{{
+				Kind: Kind_Champion, Slug: "bar",
+				Previous: &common.Champion{Slug: "bar", FactionSlug: "banner-lords"},
+			}},
+			expected: []string{"/champions/", "/champions/bar/", "/factions/banner-lords/"},
+		},
+		"faction": {
+			changes:  []Change{{Kind: Kind_Faction, Slug: "dark-elves"}},
+			expected: []string{"/champions/kael/", "/factions/", "/factions/dark-elves/"},
+		},
+		"status effect": {
+			changes:  []Change{{Kind: Kind_StatusEffect, Slug: "poison"}},
+			expected: []string{"/champions/kael/", "/effects/", "/effects/poison/"},
+		},
+		"fusion": {
+			changes:  []Change{{Kind: Kind_Fusion, Slug: "fusion-foo"}},
+			expected: []string{"/champions/foo/", "/champions/kael/", "/fusions/", "/fusions/fusion-foo/"},
+		},
+	} {
+		pages, errPages := Pages(test.changes)
+		if errPages != nil {
+			t.Errorf("%s: %s", name, errPages)
+			continue
+		}
+		if !reflect.DeepEqual(pages, test.expected) {
+			t.Errorf("%s: expected %v, got %v", name, test.expected, pages)
+		}
+	}
+}
+
+func TestPagesUnknownKind(t *testing.T) {
+	if errFactory := common.InitFactory(writeTestData(t, testChampions)); errFactory != nil {
+		t.Fatal(errFactory)
+	}
+	if _, errPages := Pages([]Change{{Kind: "masteries", Slug: "deadly-precision"}}); errPages == nil {
+		t.Error("expected an error for an unknown kind")
+	}
+}
+
+func TestTags(t *testing.T) {
+	tags := Tags([]Change{
+		{Kind: Kind_Faction, Slug: "dark-elves"},
+		{Kind: Kind_Champion, Slug: "kael"},
+		{Kind: Kind_Champion, Slug: "kael"},
+	})
+	expected := []string{"champions-kael", "factions-dark-elves"}
+	if !reflect.DeepEqual(tags, expected) {
+		t.Errorf("expected %v, got %v", expected, tags)
+	}
+}
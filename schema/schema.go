@@ -0,0 +1,154 @@
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/raid-codex/tools/common"
+)
+
+// Draft is the JSON Schema version of generated schemas
+const Draft = "http://json-schema.org/draft-07/schema#"
+
+// Schema is a JSON Schema document
+type Schema map[string]interface{}
+
+// Entity is a kind of data file, Directory is its folder in docs/
+type Entity struct {
+	Name      string
+	Directory string
+	Type      reflect.Type
+}
+
+var (
+	// Entities lists the data kept in the data directory
+	Entities = []Entity{
+		{Name: "champion", Directory: "champions", Type: reflect.TypeOf(common.Champion{})},
+		{Name: "faction", Directory: "factions", Type: reflect.TypeOf(common.Faction{})},
+		{Name: "status-effect", Directory: "status-effects", Type: reflect.TypeOf(common.StatusEffect{})},
+		{Name: "fusion", Directory: "fusions", Type: reflect.TypeOf(common.Fusion{})},
+		{Name: "mastery", Directory: "masteries", Type: reflect.TypeOf(common.Mastery{})},
+	}
+
+	// Enums restricts the values of fields, keys are "Type.json_name", "Type.*" for every field
+	// of a type, or "Type" for named string types
+	Enums = map[string][]string{
+		"Champion.rarity":   common.Rarities,
+		"Champion.element":  append([]string{""}, common.Elements...),
+		"Champion.type":     common.ChampionTypes,
+		"Rating.*":          common.RatingGrades,
+		"SynergyContextKey": synergyContextKeys(),
+	}
+
+	timeType = reflect.TypeOf(time.Time{})
+	rawType  = reflect.TypeOf(json.RawMessage{})
+)
+
+func synergyContextKeys() []string {
+	keys := make([]string, len(common.SynergyContextKeys))
+	for idx, key := range common.SynergyContextKeys {
+		keys[idx] = string(key)
+	}
+	return keys
+}
+
+// Generate returns the schema of a single file of the entity
+func (e *Entity) Generate() Schema {
+	g := &generator{definitions: map[string]Schema{}}
+	root := g.schemaOf(e.Type, "")
+	root["$schema"] = Draft
+	root["title"] = e.Name
+	root["definitions"] = g.definitions
+	return root
+}
+
+// GenerateIndex returns the schema of the index.json file of the entity, a list of entities
+func (e *Entity) GenerateIndex() Schema {
+	g := &generator{definitions: map[string]Schema{}}
+	return Schema{
+		"$schema":     Draft,
+		"title":       fmt.Sprintf("%s index", e.Name),
+		"type":        "array",
+		"items":       g.schemaOf(e.Type, ""),
+		"definitions": g.definitions,
+	}
+}
+
+type generator struct {
+	definitions map[string]Schema
+}
+
+// schemaOf returns the schema of t, enumKey is the key of the field holding t in Enums
+func (g *generator) schemaOf(t reflect.Type, enumKey string) Schema {
+	switch t {
+	case timeType:
+		return Schema{"type": "string", "format": "date-time"}
+	case rawType:
+		return Schema{}
+	}
+	switch t.Kind() {
+	case reflect.Ptr:
+		return Schema{"anyOf": []interface{}{Schema{"type": "null"}, g.schemaOf(t.Elem(), enumKey)}}
+	case reflect.Struct:
+		g.define(t)
+		return Schema{"$ref": fmt.Sprintf("#/definitions/%s", t.Name())}
+	case reflect.Slice, reflect.Array:
+		return Schema{"type": []string{"array", "null"}, "items": g.schemaOf(t.Elem(), "")}
+	case reflect.Map:
+		schema := Schema{"type": []string{"object", "null"}, "additionalProperties": g.schemaOf(t.Elem(), "")}
+		switch t.Key().Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			schema["propertyNames"] = Schema{"pattern": "^-?[0-9]+$"}
+		}
+		return schema
+	case reflect.String:
+		schema := Schema{"type": "string"}
+		if enum, ok := Enums[t.Name()]; ok && t.PkgPath() != "" {
+			schema["enum"] = enum
+		} else if enum, ok := Enums[enumKey]; ok {
+			schema["enum"] = enum
+		}
+		return schema
+	case reflect.Bool:
+		return Schema{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return Schema{"type": "integer"}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return Schema{"type": "integer", "minimum": 0}
+	case reflect.Float32, reflect.Float64:
+		return Schema{"type": "number"}
+	}
+	return Schema{}
+}
+
+// define adds the definition of the struct t. Files written before a field was added lack it, so no
+// field is required, but unknown fields are rejected since they reveal data the types no longer describe
+func (g *generator) define(t reflect.Type) {
+	if _, ok := g.definitions[t.Name()]; ok {
+		return
+	}
+	properties := Schema{}
+	definition := Schema{"type": "object", "properties": properties, "additionalProperties": false}
+	// registered first so recursive types stop here
+	g.definitions[t.Name()] = definition
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+		if name == "-" {
+			continue
+		} else if name == "" {
+			name = field.Name
+		}
+		enumKey := fmt.Sprintf("%s.%s", t.Name(), name)
+		if _, ok := Enums[enumKey]; !ok {
+			enumKey = fmt.Sprintf("%s.*", t.Name())
+		}
+		properties[name] = g.schemaOf(field.Type, enumKey)
+	}
+}
@@ -0,0 +1,72 @@
+package schema
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/xeipuuv/gojsonschema"
+)
+
+// FileError lists the reasons a data file does not match its schema
+type FileError struct {
+	Filename string
+	Errors   []string
+}
+
+func (fe *FileError) Error() string {
+	return fmt.Sprintf("%s:\n - %s", fe.Filename, strings.Join(fe.Errors, "\n - "))
+}
+
+// Validate checks a document against a schema
+func Validate(schema Schema, filename string) (*FileError, error) {
+	abs, errAbs := filepath.Abs(filename)
+	if errAbs != nil {
+		return nil, errAbs
+	}
+	result, errValidate := gojsonschema.Validate(gojsonschema.NewGoLoader(schema), gojsonschema.NewReferenceLoader(fmt.Sprintf("file://%s", abs)))
+	if errValidate != nil {
+		return nil, errors.Annotatef(errValidate, "cannot validate %s", filename)
+	}
+	if result.Valid() {
+		return nil, nil
+	}
+	fileError := &FileError{Filename: filename, Errors: make([]string, 0, len(result.Errors()))}
+	for _, desc := range result.Errors() {
+		fileError.Errors = append(fileError.Errors, desc.String())
+	}
+	return fileError, nil
+}
+
+// ValidateDataDirectory checks every file of docs/<entity>/current against the generated schemas,
+// index.json against the index schema
+func ValidateDataDirectory(dataDirectory string) ([]*FileError, error) {
+	fileErrors := make([]*FileError, 0)
+	for _, entity := range Entities {
+		directory := filepath.Join(dataDirectory, "docs", entity.Directory, "current")
+		files, errDir := ioutil.ReadDir(directory)
+		if errDir != nil {
+			return nil, errDir
+		}
+		entitySchema, indexSchema := entity.Generate(), entity.GenerateIndex()
+		for _, file := range files {
+			if file.IsDir() || !strings.HasSuffix(file.Name(), ".json") {
+				continue
+			}
+			schema := entitySchema
+			if file.Name() == "index.json" {
+				schema = indexSchema
+			}
+			fileError, errValidate := Validate(schema, filepath.Join(directory, file.Name()))
+			if errValidate != nil {
+				return nil, errValidate
+			}
+			if fileError != nil {
+				fileErrors = append(fileErrors, fileError)
+			}
+		}
+	}
+	return fileErrors, nil
+}
@@ -0,0 +1,60 @@
+package screenshot
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/raid-codex/tools/common"
+)
+
+// maxNameWords is the number of words of the longest champion names
+const maxNameWords = 4
+
+// Match is the champion named in a text
+type Match struct {
+	Champion *common.Champion
+	// Fuzzy is the part of the text taken for the name of the champion when it is only close to it,
+	// it is empty when the text holds the name
+	Fuzzy string
+}
+
+// MatchChampion returns the champion named in text, the text read on a screenshot or a file name.
+// Every sequence of up to maxNameWords words is tried, longest first, so the level and other noise
+// around the name are ignored, then the closest name is taken to tolerate misread letters and the
+// match is fuzzy.
+func MatchChampion(text string) (*Match, error) {
+	champions, errChampions := common.GetChampions()
+	if errChampions != nil {
+		return nil, errChampions
+	}
+	names := make([]string, len(champions))
+	byName := make(map[string]*common.Champion, len(champions))
+	for idx, champion := range champions {
+		names[idx] = champion.Name
+		byName[champion.Name] = champion
+	}
+	words := strings.FieldsFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r) && r != '\'' && r != '’'
+	})
+	candidates := make([]string, 0)
+	for size := maxNameWords; size > 0; size-- {
+		for start := 0; start+size <= len(words); start++ {
+			candidates = append(candidates, strings.Join(words[start:start+size], " "))
+		}
+	}
+	for _, candidate := range candidates {
+		if match, ok := common.MatchName(candidate, names); ok {
+			return &Match{Champion: byName[match]}, nil
+		}
+	}
+	for _, candidate := range candidates {
+		// short words are too likely to be close to a name by chance
+		if len(common.NormalizeName(candidate)) < 4 {
+			continue
+		}
+		if suggestions := common.SuggestNames(candidate, names, 1); len(suggestions) > 0 {
+			return &Match{Champion: byName[suggestions[0]], Fuzzy: candidate}, nil
+		}
+	}
+	return nil, common.NewChampionNotFoundError(strings.TrimSpace(text), names)
+}
@@ -0,0 +1,53 @@
+package screenshot
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/png"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// OCR reads the text of an image
+type OCR interface {
+	Text(img image.Image) (string, error)
+}
+
+// Tesseract runs the tesseract command line
+type Tesseract struct {
+	// Binary is the path of tesseract, looked up in PATH when empty
+	Binary   string
+	Language string
+}
+
+func (t *Tesseract) Text(img image.Image) (string, error) {
+	file, errTemp := ioutil.TempFile("", "raid-codex-ocr-*.png")
+	if errTemp != nil {
+		return "", errTemp
+	}
+	defer os.Remove(file.Name())
+	errEncode := png.Encode(file, img)
+	file.Close()
+	if errEncode != nil {
+		return "", errEncode
+	}
+	binary := t.Binary
+	if binary == "" {
+		binary = "tesseract"
+	}
+	args := []string{file.Name(), "stdout"}
+	if t.Language != "" {
+		args = append(args, "-l", t.Language)
+	}
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command(binary, args...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if errRun := cmd.Run(); errRun != nil {
+		return "", fmt.Errorf("%s: %s %s", binary, errRun, strings.TrimSpace(stderr.String()))
+	}
+	return stdout.String(), nil
+}
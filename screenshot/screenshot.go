@@ -0,0 +1,171 @@
+package screenshot
+
+import (
+	"fmt"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/raid-codex/tools/common"
+	"github.com/raid-codex/tools/media"
+)
+
+// Reference size of the screenshots the regions are measured on, screenshots of another size are
+// cropped at the same proportions
+const (
+	ReferenceWidth  = 1334
+	ReferenceHeight = 750
+)
+
+// Region is a rectangle of a reference screenshot of the champion page of the game
+type Region struct {
+	X, Y, Width, Height int
+}
+
+var (
+	// Region_Title holds the name and the level of the champion
+	Region_Title = Region{X: 0, Y: 0, Width: 1334, Height: 55}
+	// Region_Portrait is the full image of the champion
+	Region_Portrait = Region{X: 225, Y: 55, Width: 600, Height: 600}
+	// Region_Stats holds the statistics of the champion
+	Region_Stats = Region{X: 1000, Y: 400, Width: 334, Height: 350}
+)
+
+// Crop returns the region of img, scaled to the size of img
+func (r Region) Crop(img image.Image) image.Image {
+	bounds := img.Bounds()
+	scaleX := float64(bounds.Dx()) / ReferenceWidth
+	scaleY := float64(bounds.Dy()) / ReferenceHeight
+	rect := image.Rect(
+		bounds.Min.X+int(float64(r.X)*scaleX),
+		bounds.Min.Y+int(float64(r.Y)*scaleY),
+		bounds.Min.X+int(float64(r.X+r.Width)*scaleX),
+		bounds.Min.Y+int(float64(r.Y+r.Height)*scaleY),
+	).Intersect(bounds)
+	cropped := image.NewRGBA(image.Rect(0, 0, rect.Dx(), rect.Dy()))
+	for y := 0; y < rect.Dy(); y++ {
+		for x := 0; x < rect.Dx(); x++ {
+			cropped.Set(x, y, img.At(rect.Min.X+x, rect.Min.Y+y))
+		}
+	}
+	return cropped
+}
+
+// Processor turns screenshots of the champion page of the game into the images of the website
+type Processor struct {
+	// OCR reads the name of the champion on the screenshot, the name is taken from the file name
+	// of the screenshot when nil or when the text read matches no champion exactly
+	OCR OCR
+	// OutputDirectory receives the images laid out like wp-content/uploads: champions/ for the
+	// portraits, hashed-img/ for the thumbnails and screenshots/ for the screenshots and their
+	// statistics
+	OutputDirectory string
+	ThumbnailSize   int
+	// AcceptFuzzy uses the champion whose name is only close to the name read, such screenshots
+	// fail otherwise so that a misread name does not overwrite the images of another champion
+	AcceptFuzzy bool
+}
+
+// Result lists what was written for a screenshot
+type Result struct {
+	Screenshot string
+	Champion   *common.Champion
+	// Fuzzy is the name read when it is only close to the name of the champion
+	Fuzzy string
+	Files []string
+}
+
+// Process crops the screenshot and writes its images, under the name of the champion it shows
+func (p *Processor) Process(filename string) (*Result, error) {
+	file, errOpen := os.Open(filename)
+	if errOpen != nil {
+		return nil, errOpen
+	}
+	defer file.Close()
+	img, _, errDecode := image.Decode(file)
+	if errDecode != nil {
+		return nil, errors.Annotatef(errDecode, "cannot decode %s", filename)
+	}
+	// the file name is used when there is no OCR or when the OCR reads no name
+	name := strings.TrimSuffix(filepath.Base(filename), filepath.Ext(filename))
+	for _, prefix := range []string{"screenshot-champion-", "image-champion-"} {
+		name = strings.TrimPrefix(name, prefix)
+	}
+	var match *Match
+	if p.OCR != nil {
+		text, errOCR := p.OCR.Text(Region_Title.Crop(img))
+		if errOCR != nil {
+			return nil, errors.Annotatef(errOCR, "cannot read the title of %s", filename)
+		}
+		match, _ = MatchChampion(text)
+	}
+	if match == nil || match.Fuzzy != "" {
+		// an exact file name is preferred to a fuzzy OCR match
+		byName, errChampion := MatchChampion(name)
+		if errChampion != nil && match == nil {
+			return nil, errChampion
+		} else if errChampion == nil && (match == nil || byName.Fuzzy == "") {
+			match = byName
+		}
+	}
+	if match.Fuzzy != "" && !p.AcceptFuzzy {
+		return nil, errors.Errorf("%q is only close to %s and fuzzy matches are not accepted", match.Fuzzy, match.Champion.Name)
+	}
+	champion := match.Champion
+	result := &Result{Screenshot: filename, Champion: champion, Fuzzy: match.Fuzzy}
+	write := func(path string, encode func(*os.File) error) error {
+		full := filepath.Join(p.OutputDirectory, path)
+		if errDir := os.MkdirAll(filepath.Dir(full), 0755); errDir != nil {
+			return errDir
+		}
+		out, errCreate := os.Create(full)
+		if errCreate != nil {
+			return errCreate
+		}
+		defer out.Close()
+		if errEncode := encode(out); errEncode != nil {
+			return errors.Annotatef(errEncode, "cannot write %s", full)
+		}
+		result.Files = append(result.Files, full)
+		return nil
+	}
+	portrait := Region_Portrait.Crop(img)
+	errPortrait := write(common.ChampionImagePath(champion.Slug), func(out *os.File) error {
+		return jpeg.Encode(out, portrait, &jpeg.Options{Quality: 90})
+	})
+	if errPortrait != nil {
+		return nil, errPortrait
+	}
+	if thumbnailPath := media.ChampionThumbnailPath(champion.Thumbnail); thumbnailPath != "" && p.ThumbnailSize > 0 {
+		thumbnail := media.Resize(portrait, p.ThumbnailSize, p.ThumbnailSize)
+		errThumbnail := write(thumbnailPath, func(out *os.File) error {
+			return png.Encode(out, thumbnail)
+		})
+		if errThumbnail != nil {
+			return nil, errThumbnail
+		}
+	}
+	errStats := write(fmt.Sprintf("screenshots/stats-champion-%s.png", champion.Slug), func(out *os.File) error {
+		return png.Encode(out, Region_Stats.Crop(img))
+	})
+	if errStats != nil {
+		return nil, errStats
+	}
+	errScreenshot := write(fmt.Sprintf("screenshots/screenshot-champion-%s%s", champion.Slug, strings.ToLower(filepath.Ext(filename))), func(out *os.File) error {
+		data, errRead := ioutil.ReadFile(filename)
+		if errRead != nil {
+			return errRead
+		}
+		_, errWrite := out.Write(data)
+		return errWrite
+	})
+	if errScreenshot != nil {
+		return nil, errScreenshot
+	}
+	return result, nil
+}
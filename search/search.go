@@ -0,0 +1,276 @@
+package search
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/raid-codex/tools/common"
+)
+
+const (
+	Kind_Champion     = "champion"
+	Kind_Skill        = "skill"
+	Kind_StatusEffect = "status-effect"
+	Kind_Faction      = "faction"
+)
+
+// Document is something a search can return
+type Document struct {
+	Kind  string `json:"kind"`
+	Title string `json:"title"`
+	URL   string `json:"url"`
+	// Champion is the slug of the champion the document belongs to, if any
+	Champion string `json:"champion,omitempty"`
+}
+
+// Posting is a document containing a term, with the weight of the best field it appears in
+type Posting struct {
+	Document int     `json:"d"`
+	Weight   float64 `json:"w"`
+}
+
+// Index is an inverted index from terms to the documents containing them
+type Index struct {
+	Documents []*Document          `json:"documents"`
+	Terms     map[string][]Posting `json:"terms"`
+
+	sortedTerms []string
+}
+
+// Result is a document matching a query
+type Result struct {
+	*Document
+	Score float64 `json:"score"`
+}
+
+// field weights, a name weighs more than a word of a description
+const (
+	weight_Name        = 10.0
+	weight_SkillName   = 6.0
+	weight_Effect      = 3.0
+	weight_Faction     = 3.0
+	weight_Description = 1.0
+	weight_Lore        = 0.5
+)
+
+// prefixPenalty is applied to terms only matched by prefix
+const prefixPenalty = 0.5
+
+var (
+	stopWords = map[string]bool{
+		"a": true, "an": true, "and": true, "the": true, "of": true, "to": true, "by": true, "for": true,
+		"in": true, "on": true, "with": true, "is": true, "it": true, "its": true, "or": true, "if": true,
+		"this": true, "that": true, "at": true, "from": true, "has": true, "be": true, "will": true,
+	}
+	// synonyms expands the shorthands players use to the words of skill descriptions
+	synonyms = map[string][]string{
+		"aoe": {"all", "enemies"},
+		"tm":  {"turn", "meter"},
+		"atk": {"attack"},
+		"def": {"defense"},
+		"spd": {"speed"},
+		"acc": {"accuracy"},
+		"res": {"resistance"},
+		"cd":  {"cooldown"},
+	}
+	tagRegexp = regexp.MustCompile(`<[^>]*>`)
+)
+
+// Tokenize lowercases text and splits it into words, dropping punctuation and stop words. Text may
+// be HTML, like lores and skill descriptions: tags are dropped and entities decoded.
+func Tokenize(text string) []string {
+	text = html.UnescapeString(tagRegexp.ReplaceAllString(text, " "))
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '\''
+	})
+	tokens := make([]string, 0, len(words))
+	for _, word := range words {
+		word = strings.Trim(strings.Replace(word, "'s", "", -1), "'")
+		word = strings.Replace(word, "'", "", -1)
+		if word == "" || stopWords[word] {
+			continue
+		}
+		tokens = append(tokens, word)
+	}
+	return tokens
+}
+
+// New returns an empty index
+func New() *Index {
+	return &Index{Documents: make([]*Document, 0), Terms: map[string][]Posting{}}
+}
+
+// Build indexes champions with their skills, status effects and factions
+func Build(champions common.ChampionList, effects common.StatusEffectList, factions common.FactionList) *Index {
+	idx := New()
+	for _, champion := range champions {
+		doc := idx.Add(&Document{Kind: Kind_Champion, Title: champion.Name, URL: champion.WebsiteLink, Champion: champion.Slug})
+		idx.AddText(doc, champion.Name, weight_Name)
+		idx.AddText(doc, champion.Faction.Name, weight_Faction)
+		idx.AddText(doc, champion.Lore, weight_Lore)
+		// the whole text of the champion is added before the documents of its skills
+		for _, skill := range champion.Skills {
+			idx.AddText(doc, skill.Name, weight_SkillName)
+			idx.AddText(doc, skill.RawDescription, weight_Description)
+			for _, effect := range skill.Effects {
+				idx.AddText(doc, effect.Type, weight_Effect)
+			}
+		}
+		for _, skill := range champion.Skills {
+			skillDoc := idx.Add(&Document{
+				Kind:     Kind_Skill,
+				Title:    fmt.Sprintf("%s (%s)", skill.Name, champion.Name),
+				URL:      champion.WebsiteLink,
+				Champion: champion.Slug,
+			})
+			idx.AddText(skillDoc, skill.Name, weight_Name)
+			idx.AddText(skillDoc, champion.Name, weight_Faction)
+			idx.AddText(skillDoc, skill.RawDescription, weight_Description*2)
+			for _, effect := range skill.Effects {
+				idx.AddText(skillDoc, effect.Type, weight_Effect)
+			}
+		}
+	}
+	for _, effect := range effects {
+		doc := idx.Add(&Document{Kind: Kind_StatusEffect, Title: effect.Type, URL: effect.WebsiteLink})
+		idx.AddText(doc, effect.Type, weight_Name)
+		idx.AddText(doc, effect.EffectType, weight_Effect)
+		idx.AddText(doc, effect.RawDescription, weight_Description)
+	}
+	for _, faction := range factions {
+		doc := idx.Add(&Document{Kind: Kind_Faction, Title: faction.Name, URL: faction.WebsiteLink})
+		idx.AddText(doc, faction.Name, weight_Name)
+	}
+	idx.SortTerms()
+	return idx
+}
+
+// Add registers a document and returns its position, used to index its text
+func (idx *Index) Add(doc *Document) int {
+	idx.Documents = append(idx.Documents, doc)
+	return len(idx.Documents) - 1
+}
+
+// AddText indexes the words of text for the document, keeping the highest weight of each term.
+// The whole text of a document must be added before the next document is, so that its postings
+// are coalesced and stay sorted by document.
+func (idx *Index) AddText(doc int, text string, weight float64) {
+	for _, token := range Tokenize(text) {
+		postings := idx.Terms[token]
+		if last := len(postings) - 1; last >= 0 && postings[last].Document == doc {
+			if postings[last].Weight < weight {
+				postings[last].Weight = weight
+			}
+			continue
+		}
+		idx.Terms[token] = append(postings, Posting{Document: doc, Weight: weight})
+	}
+	idx.sortedTerms = nil
+}
+
+// SortTerms prepares prefix matching once every text is added, so searches never modify the index
+// and can run concurrently
+func (idx *Index) SortTerms() {
+	sortedTerms := make([]string, 0, len(idx.Terms))
+	for term := range idx.Terms {
+		sortedTerms = append(sortedTerms, term)
+	}
+	sort.Strings(sortedTerms)
+	idx.sortedTerms = sortedTerms
+}
+
+// Search returns the documents matching every word of query, best first. Words match terms
+// exactly or by prefix, with a lower score for prefixes.
+func (idx *Index) Search(query string, limit int) []*Result {
+	tokens := make([]string, 0)
+	for _, token := range Tokenize(query) {
+		if expanded, ok := synonyms[token]; ok {
+			tokens = append(tokens, expanded...)
+		} else {
+			tokens = append(tokens, token)
+		}
+	}
+	if len(tokens) == 0 {
+		return []*Result{}
+	}
+	var scores map[int]float64
+	for _, token := range tokens {
+		tokenScores := idx.scoreToken(token)
+		if scores == nil {
+			scores = tokenScores
+			continue
+		}
+		for doc, score := range scores {
+			if tokenScore, ok := tokenScores[doc]; ok {
+				scores[doc] = score + tokenScore
+			} else {
+				delete(scores, doc)
+			}
+		}
+	}
+	results := make([]*Result, 0, len(scores))
+	for doc, score := range scores {
+		results = append(results, &Result{Document: idx.Documents[doc], Score: score})
+	}
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].Score == results[j].Score {
+			return results[i].Title < results[j].Title
+		}
+		return results[i].Score > results[j].Score
+	})
+	if limit > 0 && len(results) > limit {
+		results = results[:limit]
+	}
+	return results
+}
+
+// scoreToken returns the best score of each document containing the token or a term starting with it
+func (idx *Index) scoreToken(token string) map[int]float64 {
+	scores := map[int]float64{}
+	for _, term := range idx.prefixed(token) {
+		factor := 1.0
+		if term != token {
+			factor = prefixPenalty
+		}
+		for _, posting := range idx.Terms[term] {
+			if score := posting.Weight * factor; score > scores[posting.Document] {
+				scores[posting.Document] = score
+			}
+		}
+	}
+	return scores
+}
+
+// prefixed returns the terms starting with prefix
+func (idx *Index) prefixed(prefix string) []string {
+	if idx.sortedTerms == nil {
+		idx.SortTerms()
+	}
+	start := sort.SearchStrings(idx.sortedTerms, prefix)
+	end := start
+	for end < len(idx.sortedTerms) && strings.HasPrefix(idx.sortedTerms[end], prefix) {
+		end++
+	}
+	return idx.sortedTerms[start:end]
+}
+
+// FromFactory builds the index of the data loaded in the factory
+func FromFactory() (*Index, error) {
+	champions, errChampions := common.GetChampions()
+	if errChampions != nil {
+		return nil, errChampions
+	}
+	effects, errEffects := common.GetStatuseffects()
+	if errEffects != nil {
+		return nil, errEffects
+	}
+	factions, errFactions := common.GetFactions()
+	if errFactions != nil {
+		return nil, errFactions
+	}
+	return Build(champions, effects, factions), nil
+}
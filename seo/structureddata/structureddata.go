@@ -0,0 +1,280 @@
+package structureddata
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Context is the vocabulary of every document
+const Context = "https://schema.org"
+
+// Node is a JSON-LD object, its type is under "@type"
+type Node map[string]interface{}
+
+// Crumb is a named link, used for breadcrumbs and item lists
+type Crumb struct {
+	Name string
+	URL  string
+}
+
+// Breadcrumbs returns the BreadcrumbList of crumbs, from the home page to the current page
+func Breadcrumbs(crumbs ...Crumb) Node {
+	elements := make([]Node, len(crumbs))
+	for idx, crumb := range crumbs {
+		elements[idx] = Node{"@type": "ListItem", "position": idx + 1, "name": crumb.Name, "item": crumb.URL}
+	}
+	return Node{"@type": "BreadcrumbList", "itemListElement": elements}
+}
+
+// ItemList returns an ordered list of links, order is one of the ItemListOrderType members
+// (e.g. https://schema.org/ItemListOrderDescending) or empty for an unordered list
+func ItemList(name, order string, items ...Crumb) Node {
+	elements := make([]Node, len(items))
+	for idx, item := range items {
+		elements[idx] = Node{"@type": "ListItem", "position": idx + 1, "name": item.Name, "url": item.URL}
+	}
+	node := Node{"@type": "ItemList", "name": name, "numberOfItems": len(items), "itemListElement": elements}
+	if order != "" {
+		node["itemListOrder"] = order
+	}
+	return node
+}
+
+// Marshal validates nodes and returns them as JSON-LD documents
+func Marshal(nodes ...Node) ([]json.RawMessage, error) {
+	documents := make([]json.RawMessage, 0, len(nodes))
+	for _, node := range nodes {
+		node["@context"] = Context
+		if errValidate := Validate(node); errValidate != nil {
+			return nil, errValidate
+		}
+		data, errJSON := json.Marshal(node)
+		if errJSON != nil {
+			return nil, errJSON
+		}
+		documents = append(documents, json.RawMessage(data))
+	}
+	return documents, nil
+}
+
+//go:embed vocabulary.json
+var rawVocabulary []byte
+
+// Type is a type of the bundled vocabulary, a subset of schema.org
+type Type struct {
+	Parent string `json:"parent"`
+	// Required lists the properties search engines expect for the type
+	Required []string `json:"required"`
+	// Properties maps the properties of the type to the types of their values, properties of the
+	// parents are inherited
+	Properties map[string][]string `json:"properties"`
+	// Enumeration lists the allowed values of an enumeration type
+	Enumeration []string `json:"enumeration"`
+}
+
+var (
+	vocabulary = map[string]*Type{}
+	dataTypes  = map[string]func(interface{}) bool{
+		"Text":     isString,
+		"URL":      isURL,
+		"Date":     isDate,
+		"DateTime": isDateTime,
+		"Integer":  isInteger,
+		"Number":   isNumber,
+		"Boolean":  func(v interface{}) bool { _, ok := v.(bool); return ok },
+	}
+)
+
+func init() {
+	if err := json.Unmarshal(rawVocabulary, &vocabulary); err != nil {
+		panic(err)
+	}
+}
+
+// ValidationError lists every problem found in a document
+type ValidationError struct {
+	Type     string
+	Problems []string
+}
+
+func (ve *ValidationError) Error() string {
+	return fmt.Sprintf("invalid %s structured data: %s", ve.Type, strings.Join(ve.Problems, ", "))
+}
+
+// Validate checks a document against the bundled vocabulary: types must be known, properties
+// allowed on their type, values of the expected types and required properties present
+func Validate(node Node) error {
+	v := &validator{}
+	v.node("$", map[string]interface{}(node), nil)
+	if len(v.problems) > 0 {
+		typ, _ := node["@type"].(string)
+		return &ValidationError{Type: typ, Problems: v.problems}
+	}
+	return nil
+}
+
+type validator struct {
+	problems []string
+}
+
+func (v *validator) addf(format string, args ...interface{}) {
+	v.problems = append(v.problems, fmt.Sprintf(format, args...))
+}
+
+// node validates an object, expected lists the types it may have, any type when empty
+func (v *validator) node(path string, node map[string]interface{}, expected []string) bool {
+	typeName, _ := node["@type"].(string)
+	typ, ok := vocabulary[typeName]
+	if !ok || typ.Enumeration != nil {
+		v.addf("%s: unknown type %q", path, typeName)
+		return false
+	}
+	if len(expected) > 0 && !isSubtypeOfAny(typeName, expected) {
+		v.addf("%s: %s is not a %s", path, typeName, strings.Join(expected, " or "))
+		return false
+	}
+	for _, required := range requiredProperties(typeName) {
+		if value, ok := node[required]; !ok || value == nil || value == "" {
+			v.addf("%s: missing %s on %s", path, required, typeName)
+		}
+	}
+	keys := make([]string, 0, len(node))
+	for key := range node {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if strings.HasPrefix(key, "@") {
+			continue
+		}
+		ranges := propertyRanges(typeName, key)
+		if ranges == nil {
+			v.addf("%s: %s has no property %s", path, typeName, key)
+			continue
+		}
+		v.value(fmt.Sprintf("%s.%s", path, key), node[key], ranges)
+	}
+	return true
+}
+
+func (v *validator) value(path string, value interface{}, ranges []string) {
+	switch typed := value.(type) {
+	case Node:
+		v.node(path, map[string]interface{}(typed), ranges)
+	case map[string]interface{}:
+		v.node(path, typed, ranges)
+	case []Node:
+		for idx, item := range typed {
+			v.node(fmt.Sprintf("%s[%d]", path, idx), map[string]interface{}(item), ranges)
+		}
+	case []interface{}:
+		for idx, item := range typed {
+			v.value(fmt.Sprintf("%s[%d]", path, idx), item, ranges)
+		}
+	case []string:
+		for idx, item := range typed {
+			v.value(fmt.Sprintf("%s[%d]", path, idx), item, ranges)
+		}
+	default:
+		for _, r := range ranges {
+			if check, ok := dataTypes[r]; ok && check(value) {
+				return
+			} else if typ, ok := vocabulary[r]; ok && typ.Enumeration != nil && contains(typ.Enumeration, value) {
+				return
+			}
+		}
+		v.addf("%s: %v is not a %s", path, value, strings.Join(ranges, " or "))
+	}
+}
+
+func isSubtypeOfAny(typeName string, expected []string) bool {
+	for name := typeName; name != ""; name = vocabulary[name].Parent {
+		for _, e := range expected {
+			if e == name {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func requiredProperties(typeName string) []string {
+	required := make([]string, 0)
+	for name := typeName; name != ""; name = vocabulary[name].Parent {
+		required = append(required, vocabulary[name].Required...)
+	}
+	return required
+}
+
+func propertyRanges(typeName, property string) []string {
+	for name := typeName; name != ""; name = vocabulary[name].Parent {
+		if ranges, ok := vocabulary[name].Properties[property]; ok {
+			return ranges
+		}
+	}
+	return nil
+}
+
+func contains(list []string, value interface{}) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
+
+func isString(v interface{}) bool {
+	_, ok := v.(string)
+	return ok
+}
+
+func isURL(v interface{}) bool {
+	s, ok := v.(string)
+	if !ok {
+		return false
+	}
+	u, errParse := url.Parse(s)
+	return errParse == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
+func isDate(v interface{}) bool {
+	s, ok := v.(string)
+	if !ok {
+		return false
+	}
+	_, errParse := time.Parse("2006-01-02", s)
+	return errParse == nil
+}
+
+func isDateTime(v interface{}) bool {
+	s, ok := v.(string)
+	if !ok {
+		return false
+	}
+	_, errParse := time.Parse(time.RFC3339, s)
+	return errParse == nil
+}
+
+func isInteger(v interface{}) bool {
+	switch n := v.(type) {
+	case int, int32, int64:
+		return true
+	case float64:
+		return n == float64(int64(n))
+	}
+	return false
+}
+
+func isNumber(v interface{}) bool {
+	switch v.(type) {
+	case int, int32, int64, float32, float64:
+		return true
+	}
+	return false
+}
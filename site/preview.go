@@ -0,0 +1,160 @@
+package site
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/raid-codex/tools/common"
+	"github.com/raid-codex/tools/common/paged"
+	"github.com/raid-codex/tools/publish"
+	"github.com/raid-codex/tools/templatefuncs"
+)
+
+// Preview renders pages on demand, from the data files and the templates as they are on disk, so
+// editors see a change before publishing it with the page create commands
+type Preview struct {
+	DataDirectory  string
+	TemplateFolder string
+	PageTemplate   string
+
+	funcMap   template.FuncMap
+	templates *TemplateCache
+}
+
+// NewPreview returns a preview whose links between pages are prefixed with root, e.g. /web
+func NewPreview(dataDirectory, templateFolder, pageTemplate, root string) *Preview {
+	funcMap := templatefuncs.WithWebsiteRoot(root)
+	return &Preview{
+		DataDirectory:  dataDirectory,
+		TemplateFolder: templateFolder,
+		PageTemplate:   pageTemplate,
+		funcMap:        funcMap,
+		templates:      NewTemplateCache(funcMap),
+	}
+}
+
+// Index renders the list of every entity, grouped by section
+func (p *Preview) Index() ([]byte, error) {
+	sections, errSections := Sections()
+	if errSections != nil {
+		return nil, errSections
+	}
+	return p.embedded("preview.html", "RAID - Codex preview", map[string]interface{}{"Sections": sections})
+}
+
+// List renders the list of a section, name is its link without slashes, e.g. effects
+func (p *Preview) List(name string) ([]byte, error) {
+	section, errSection := p.section(name)
+	if errSection != nil {
+		return nil, errSection
+	}
+	structuredData, errStructuredData := listStructuredData(section, common.DefaultWebsiteRoot)
+	if errStructuredData != nil {
+		return nil, errors.Annotatef(errStructuredData, "cannot describe %s", section.Link)
+	}
+	data := map[string]interface{}{"Title": section.Title, "Pages": section.Pages, "StructuredData": structuredData}
+	return p.embedded("list.html", section.Title, data)
+}
+
+// Page renders the page of an entity of a section, read from its data file when there is one so
+// the file can be edited without restarting, and from the factory otherwise
+func (p *Preview) Page(name, slug string) ([]byte, error) {
+	section, errSection := p.section(name)
+	if errSection != nil {
+		return nil, errSection
+	}
+	entity, errEntity := p.entity(section, slug)
+	if errEntity != nil {
+		return nil, errEntity
+	}
+	tmpl, errTemplates := p.templates.Folder(filepath.Join(p.TemplateFolder, section.TemplateDirectory))
+	if errTemplates != nil {
+		return nil, errTemplates
+	}
+	content, errContent := publish.Content(entity, p.TemplateFolder, p.DataDirectory, tmpl)
+	if errContent != nil {
+		return nil, errors.Annotatef(errContent, "cannot render %s", entity.GetPageSlug())
+	}
+	return p.wrap(entity.GetPageTitle(), entity.GetPageExcerpt(), content)
+}
+
+func (p *Preview) section(name string) (*Section, error) {
+	sections, errSections := Sections()
+	if errSections != nil {
+		return nil, errSections
+	}
+	for _, section := range sections {
+		if strings.Trim(section.Link, "/") == name {
+			return section, nil
+		}
+	}
+	return nil, errors.NotFoundf("section %s", name)
+}
+
+func (p *Preview) entity(section *Section, slug string) (paged.Paged, error) {
+	file, errOpen := os.Open(filepath.Join(p.DataDirectory, "docs", section.Directory, "current", fmt.Sprintf("%s.json", slug)))
+	if os.IsNotExist(errOpen) {
+		for _, page := range section.Pages {
+			if page.GetPageSlug() == slug {
+				return page, nil
+			}
+		}
+		return nil, errors.NotFoundf("%s %s", section.TemplateDirectory, slug)
+	} else if errOpen != nil {
+		return nil, errOpen
+	}
+	defer file.Close()
+	var entity paged.Paged
+	switch section.TemplateDirectory {
+	case "champion":
+		entity = &common.Champion{}
+	case "faction":
+		entity = &common.Faction{}
+	case "status-effect":
+		entity = &common.StatusEffect{}
+	case "fusion":
+		entity = &common.Fusion{}
+	default:
+		return nil, errors.NotSupportedf("section %s", section.Title)
+	}
+	if errJSON := json.NewDecoder(file).Decode(entity); errJSON != nil {
+		return nil, errors.Annotate(errJSON, "cannot unmarshal file")
+	}
+	return entity, nil
+}
+
+func (p *Preview) embedded(name, title string, data interface{}) ([]byte, error) {
+	tmpl, errTmpl := template.New(name).Funcs(p.funcMap).ParseFS(templates, "templates/"+name)
+	if errTmpl != nil {
+		return nil, errTmpl
+	}
+	buf := bytes.NewBufferString("")
+	if errExecute := tmpl.Execute(buf, data); errExecute != nil {
+		return nil, errors.Annotatef(errExecute, "cannot render %s", name)
+	}
+	return p.wrap(title, "", buf.String())
+}
+
+// wrap renders content in the page template
+func (p *Preview) wrap(title, description, content string) ([]byte, error) {
+	page, errPage := p.templates.File(p.PageTemplate)
+	if errPage != nil {
+		return nil, errors.Annotate(errPage, "cannot parse page template")
+	}
+	buf := bytes.NewBufferString("")
+	errExecute := page.Execute(buf, map[string]interface{}{
+		"Page":        content,
+		"Title":       title,
+		"Description": description,
+	})
+	if errExecute != nil {
+		return nil, errExecute
+	}
+	return buf.Bytes(), nil
+}
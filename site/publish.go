@@ -0,0 +1,193 @@
+package site
+
+import (
+	"fmt"
+	"html/template"
+	"log"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/raid-codex/tools/common"
+	"github.com/raid-codex/tools/common/paged"
+	"github.com/raid-codex/tools/publish"
+	"github.com/raid-codex/tools/templatefuncs"
+)
+
+// Sync publishes the page of every entity, like running each of the page create commands once
+// per file
+type Sync struct {
+	DataDirectory  string
+	TemplateFolder string
+	Publisher      publish.Publisher
+	// Hashes skips unchanged pages, every page is pushed when nil
+	Hashes *publish.HashStore
+	// Schedule is the status of every page, unless the page has its own in Schedules
+	Schedule  publish.Schedule
+	Schedules map[string]publish.Schedule
+	Workers   int
+}
+
+// SyncReport tells what a sync did
+type SyncReport struct {
+	Summary publish.Summary
+	// Failed lists the slugs of the pages that could not be rendered or published
+	Failed []string
+	// Orphans are the pages under the parents of the entities matching no entity
+	Orphans []*publish.Page
+}
+
+func (sr *SyncReport) String() string {
+	lines := []string{sr.Summary.String()}
+	if len(sr.Orphans) > 0 {
+		orphans := make([]string, len(sr.Orphans))
+		for idx, orphan := range sr.Orphans {
+			orphans[idx] = fmt.Sprintf("\t%s (id %d, parent %d)", orphan.Slug, orphan.ID, orphan.Parent)
+		}
+		lines = append(lines, fmt.Sprintf("%d pages no longer match an entity:\n%s", len(orphans), strings.Join(orphans, "\n")))
+	}
+	if len(sr.Failed) > 0 {
+		lines = append(lines, fmt.Sprintf("%d pages failed: %s", len(sr.Failed), strings.Join(sr.Failed, ", ")))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Run publishes every page, failures are logged and reported once every other page is published
+func (s *Sync) Run() (*SyncReport, error) {
+	sections, errSections := Sections()
+	if errSections != nil {
+		return nil, errSections
+	}
+	entityTemplates := map[string]*template.Template{}
+	for _, section := range sections {
+		tmpl, errTemplates := loadTemplates(filepath.Join(s.TemplateFolder, section.TemplateDirectory), templatefuncs.FuncMap)
+		if errTemplates != nil {
+			return nil, errTemplates
+		}
+		entityTemplates[section.TemplateDirectory] = tmpl
+	}
+	report := &SyncReport{}
+	jobs := make(chan job)
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	workers := s.Workers
+	if workers < 1 {
+		workers = 1
+	}
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range jobs {
+				result, errPublish := s.publish(entityTemplates[j.section.TemplateDirectory], j.page)
+				if errPublish != nil {
+					log.Printf("%s: %v\n", j.page.GetPageSlug(), errPublish)
+					mu.Lock()
+					report.Failed = append(report.Failed, j.page.GetPageSlug())
+					mu.Unlock()
+					continue
+				}
+				report.Summary.Add(j.page.GetPageSlug(), result)
+			}
+		}()
+	}
+	slugs := map[string]bool{}
+	for _, section := range sections {
+		for _, p := range section.Pages {
+			if !published(p) {
+				continue
+			}
+			slugs[p.GetPageSlug()] = true
+			slugs[p.LinkName()] = true
+			jobs <- job{section: section, page: p}
+		}
+	}
+	close(jobs)
+	wg.Wait()
+	if s.Hashes != nil {
+		if errSave := s.Hashes.Save(); errSave != nil {
+			return nil, errors.Annotate(errSave, "cannot save hashes")
+		}
+	}
+	sort.Strings(report.Failed)
+	orphans, errOrphans := s.orphans(slugs)
+	if errOrphans != nil {
+		return nil, errors.Annotate(errOrphans, "cannot list orphan pages")
+	}
+	report.Orphans = orphans
+	return report, nil
+}
+
+func (s *Sync) publish(tmpl *template.Template, p paged.Paged) (publish.Result, error) {
+	content, errContent := publish.Content(p, s.TemplateFolder, s.DataDirectory, tmpl)
+	if errContent != nil {
+		return "", errors.Annotate(errContent, "cannot render page")
+	}
+	schedule, ok := s.Schedules[p.GetPageSlug()]
+	if !ok {
+		schedule = s.Schedule
+	}
+	return publish.Publish(s.Publisher, s.Hashes, p, content, schedule)
+}
+
+func (s *Sync) orphans(slugs map[string]bool) ([]*publish.Page, error) {
+	orphans := make([]*publish.Page, 0)
+	for _, parent := range PageParents() {
+		pages, errPages := s.Publisher.ListPages(parent)
+		if errPages != nil {
+			return nil, errPages
+		}
+		for _, page := range pages {
+			if !slugs[page.Slug] {
+				orphans = append(orphans, page)
+			}
+		}
+	}
+	return orphans, nil
+}
+
+// PageParents returns the ids of the pages the pages of the entities are published under
+func PageParents() []int {
+	return []int{
+		common.Champion{}.GetParentPageID(),
+		common.Faction{}.GetParentPageID(),
+		common.StatusEffect{EffectType: "buff"}.GetParentPageID(),
+		common.StatusEffect{EffectType: "debuff"}.GetParentPageID(),
+		common.Fusion{}.GetParentPageID(),
+	}
+}
+
+// FusionSchedules schedules the pages of the champions of the fusions starting after now, and the
+// pages of the fusions themselves, to go live when the fusions start
+func FusionSchedules(now time.Time) (map[string]publish.Schedule, error) {
+	fusions, errFusions := common.GetFusions()
+	if errFusions != nil {
+		return nil, errFusions
+	}
+	schedules := map[string]publish.Schedule{}
+	for _, fusion := range fusions {
+		if fusion.TimeStart == nil || !fusion.TimeStart.After(now) || !published(fusion) {
+			continue
+		}
+		schedules[fusion.GetPageSlug()] = publish.Scheduled(*fusion.TimeStart)
+		if fusion.ChampionSlug != "" {
+			schedules[fusion.ChampionSlug] = publish.Scheduled(*fusion.TimeStart)
+		}
+	}
+	return schedules, nil
+}
+
+// published tells whether the entity has its own page: fusions of the same champion share a page,
+// and upgraded status effects are described on the page of the base effect
+func published(p paged.Paged) bool {
+	switch entity := p.(type) {
+	case *common.Fusion:
+		return entity.Slug == fmt.Sprintf("fusion-%s", entity.ChampionSlug)
+	case *common.StatusEffect:
+		return !strings.HasSuffix(entity.Slug, "-2")
+	}
+	return true
+}
@@ -0,0 +1,302 @@
+package site
+
+import (
+	"bytes"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/juju/errors"
+	"github.com/raid-codex/tools/common"
+	"github.com/raid-codex/tools/common/paged"
+	"github.com/raid-codex/tools/seo/structureddata"
+	"github.com/raid-codex/tools/templatefuncs"
+)
+
+//go:embed templates/*
+var templates embed.FS
+
+// Section is a kind of entity, rendered with the templates of TemplateDirectory and listed at Link
+type Section struct {
+	Title             string
+	Link              string
+	TemplateDirectory string
+	// Directory is the folder of docs/ holding the entities
+	Directory string
+	// Priority is the sitemap priority of the pages of the section
+	Priority float64
+	Pages    []paged.Paged
+}
+
+// Sections returns the entities of the factory, grouped like on the website
+func Sections() ([]*Section, error) {
+	champions, errChampions := common.GetChampions()
+	if errChampions != nil {
+		return nil, errChampions
+	}
+	factions, errFactions := common.GetFactions()
+	if errFactions != nil {
+		return nil, errFactions
+	}
+	effects, errEffects := common.GetStatuseffects()
+	if errEffects != nil {
+		return nil, errEffects
+	}
+	fusions, errFusions := common.GetFusions()
+	if errFusions != nil {
+		return nil, errFusions
+	}
+	sections := []*Section{
+		{Title: "Champions", Link: "/champions/", TemplateDirectory: "champion", Directory: "champions", Priority: 0.8},
+		{Title: "Factions", Link: "/factions/", TemplateDirectory: "faction", Directory: "factions", Priority: 0.6},
+		{Title: "Status effects", Link: "/effects/", TemplateDirectory: "status-effect", Directory: "status-effects", Priority: 0.5},
+		{Title: "Fusions", Link: "/fusions/", TemplateDirectory: "fusion", Directory: "fusions", Priority: 0.6},
+	}
+	for _, champion := range champions {
+		sections[0].Pages = append(sections[0].Pages, champion)
+	}
+	for _, faction := range factions {
+		sections[1].Pages = append(sections[1].Pages, faction)
+	}
+	for _, effect := range effects {
+		sections[2].Pages = append(sections[2].Pages, effect)
+	}
+	for _, fusion := range fusions {
+		sections[3].Pages = append(sections[3].Pages, fusion)
+	}
+	return sections, nil
+}
+
+// Builder renders the whole codex as a static site, every page is written to
+// <OutputDirectory><WebsiteLink>index.html so the site keeps the URLs of WordPress
+type Builder struct {
+	DataDirectory   string
+	TemplateFolder  string
+	PageTemplate    string
+	OutputDirectory string
+	// AssetsDirectory, when set, is copied as is to the output directory
+	AssetsDirectory string
+	// WebsiteRoot prefixes links between pages, empty for links relative to the host. Sitemaps
+	// need absolute urls and are only written when it is set.
+	WebsiteRoot string
+	Workers     int
+
+	funcMap template.FuncMap
+	page    *template.Template
+}
+
+type job struct {
+	section *Section
+	page    paged.Paged
+}
+
+// Build renders every page, list and the index. Pages failing to render are logged and reported
+// once every other page is written.
+func (b *Builder) Build() error {
+	b.funcMap = templatefuncs.WithWebsiteRoot(b.WebsiteRoot)
+	page, errPage := template.New("page").Funcs(b.funcMap).ParseFiles(b.PageTemplate)
+	if errPage != nil {
+		return errors.Annotate(errPage, "cannot parse page template")
+	}
+	b.page = page.Lookup(filepath.Base(b.PageTemplate))
+	sections, errSections := Sections()
+	if errSections != nil {
+		return errSections
+	}
+	entityTemplates := map[string]*template.Template{}
+	for _, section := range sections {
+		tmpl, errTemplates := b.loadTemplates(section.TemplateDirectory)
+		if errTemplates != nil {
+			return errTemplates
+		}
+		entityTemplates[section.TemplateDirectory] = tmpl
+	}
+	jobs := make(chan job)
+	var failed []string
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	workers := b.Workers
+	if workers < 1 {
+		workers = 1
+	}
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range jobs {
+				if errRender := b.renderPage(entityTemplates[j.section.TemplateDirectory], j.page); errRender != nil {
+					log.Printf("%s: %v\n", j.page.GetWebsiteLink(), errRender)
+					mu.Lock()
+					failed = append(failed, j.page.GetWebsiteLink())
+					mu.Unlock()
+				}
+			}
+		}()
+	}
+	links := map[string]bool{}
+	for _, section := range sections {
+		for _, p := range section.Pages {
+			if p.GetWebsiteLink() == "" || p.GetWebsiteLink() == "/" {
+				log.Printf("skipping %s, it has no link\n", p.GetPageSlug())
+				continue
+			} else if links[p.GetWebsiteLink()] {
+				log.Printf("skipping %s, its link %s is already taken\n", p.GetPageSlug(), p.GetWebsiteLink())
+				continue
+			}
+			links[p.GetWebsiteLink()] = true
+			jobs <- job{section: section, page: p}
+		}
+	}
+	close(jobs)
+	wg.Wait()
+	for _, section := range sections {
+		structuredData, errStructuredData := listStructuredData(section, b.structuredDataRoot())
+		if errStructuredData != nil {
+			return errors.Annotatef(errStructuredData, "cannot describe %s", section.Link)
+		}
+		data := map[string]interface{}{"Title": section.Title, "Pages": section.Pages, "StructuredData": structuredData}
+		if errList := b.renderEmbedded("list.html", section.Link, section.Title, data); errList != nil {
+			return errList
+		}
+	}
+	if errIndex := b.renderEmbedded("index.html", "/", "RAID - Codex", map[string]interface{}{"Sections": sections}); errIndex != nil {
+		return errIndex
+	}
+	if b.WebsiteRoot != "" {
+		if errSitemaps := Sitemaps(sections, b.DataDirectory, b.WebsiteRoot, b.OutputDirectory); errSitemaps != nil {
+			return errSitemaps
+		}
+	}
+	if b.AssetsDirectory != "" {
+		if errCopy := copyTree(b.AssetsDirectory, b.OutputDirectory); errCopy != nil {
+			return errors.Annotate(errCopy, "cannot copy assets")
+		}
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("%d pages failed to render: %s", len(failed), strings.Join(failed, ", "))
+	}
+	return nil
+}
+
+// structuredDataRoot is the root of the urls of the structured data, which must be absolute even
+// when links between pages are not
+func (b *Builder) structuredDataRoot() string {
+	if strings.HasPrefix(b.WebsiteRoot, "http://") || strings.HasPrefix(b.WebsiteRoot, "https://") {
+		return strings.TrimSuffix(b.WebsiteRoot, "/")
+	}
+	return common.DefaultWebsiteRoot
+}
+
+func (b *Builder) loadTemplates(directory string) (*template.Template, error) {
+	return loadTemplates(filepath.Join(b.TemplateFolder, directory), b.funcMap)
+}
+
+// loadTemplates parses every template of dir, main.html is the entry point
+func loadTemplates(dir string, funcMap template.FuncMap) (*template.Template, error) {
+	files, errFiles := ioutil.ReadDir(dir)
+	if errFiles != nil {
+		return nil, errFiles
+	}
+	templateFiles := make([]string, 0)
+	for _, file := range files {
+		templateFiles = append(templateFiles, filepath.Join(dir, file.Name()))
+	}
+	return template.New("main.html").Funcs(funcMap).ParseFiles(templateFiles...)
+}
+
+func (b *Builder) renderPage(tmpl *template.Template, p paged.Paged) error {
+	extraData, errData := p.GetPageExtraData(b.DataDirectory)
+	if errData != nil {
+		return errData
+	}
+	structuredData, errStructuredData := common.PageStructuredData(p, b.structuredDataRoot())
+	if errStructuredData != nil {
+		return errStructuredData
+	}
+	if structuredData != nil {
+		extraData["StructuredData"] = structuredData
+	}
+	buf := bytes.NewBufferString("")
+	if errTemplate := p.GetPageContent_Templates(tmpl, buf, extraData); errTemplate != nil {
+		return errTemplate
+	}
+	return b.write(p.GetWebsiteLink(), p.GetPageTitle(), p.GetPageExcerpt(), buf.String())
+}
+
+func (b *Builder) renderEmbedded(name, link, title string, data interface{}) error {
+	tmpl, errTmpl := template.New(name).Funcs(b.funcMap).ParseFS(templates, "templates/"+name)
+	if errTmpl != nil {
+		return errTmpl
+	}
+	buf := bytes.NewBufferString("")
+	if errExecute := tmpl.Execute(buf, data); errExecute != nil {
+		return errors.Annotatef(errExecute, "cannot render %s", link)
+	}
+	return b.write(link, title, "", buf.String())
+}
+
+// write wraps content in the page template and writes it where the link points to, the page
+// template gets the absolute url of the page for its canonical link
+func (b *Builder) write(link, title, description, content string) error {
+	filename := filepath.Join(b.OutputDirectory, filepath.FromSlash(strings.Trim(link, "/")), "index.html")
+	if errMkdir := os.MkdirAll(filepath.Dir(filename), 0755); errMkdir != nil {
+		return errMkdir
+	}
+	file, errCreate := os.Create(filename)
+	if errCreate != nil {
+		return errCreate
+	}
+	defer file.Close()
+	return b.page.Execute(file, map[string]interface{}{
+		"Page":        content,
+		"Title":       title,
+		"Description": description,
+		"URL":         b.structuredDataRoot() + link,
+	})
+}
+
+func copyTree(source, target string) error {
+	return filepath.Walk(source, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, errRel := filepath.Rel(source, path)
+		if errRel != nil {
+			return errRel
+		}
+		if info.IsDir() {
+			return os.MkdirAll(filepath.Join(target, rel), 0755)
+		}
+		content, errRead := ioutil.ReadFile(path)
+		if errRead != nil {
+			return errRead
+		}
+		return ioutil.WriteFile(filepath.Join(target, rel), content, info.Mode())
+	})
+}
+
+// listStructuredData describes the champions list as a tier list and other lists as item lists,
+// with absolute urls under root
+func listStructuredData(section *Section, root string) ([]json.RawMessage, error) {
+	if section.TemplateDirectory == "champion" {
+		champions := make(common.ChampionList, 0, len(section.Pages))
+		for _, p := range section.Pages {
+			if champion, ok := p.(*common.Champion); ok {
+				champions = append(champions, champion)
+			}
+		}
+		return common.TierListStructuredData("RAID: Shadow Legends tier list", root, champions)
+	}
+	items := make([]structureddata.Crumb, 0, len(section.Pages))
+	for _, p := range section.Pages {
+		items = append(items, structureddata.Crumb{Name: p.GetPageTitle(), URL: root + p.GetWebsiteLink()})
+	}
+	return structureddata.Marshal(structureddata.ItemList(section.Title, "https://schema.org/ItemListOrderAscending", items...))
+}
@@ -0,0 +1,221 @@
+package site
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"log"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/raid-codex/tools/common"
+	"github.com/raid-codex/tools/common/paged"
+	"github.com/raid-codex/tools/templatefuncs"
+	"github.com/raid-codex/tools/utils"
+)
+
+const (
+	// sitemapMaxURLs is the limit of the protocol, bigger sections are split
+	sitemapMaxURLs = 50000
+	// priority of the home and list pages
+	priority_Home = 1.0
+	priority_List = 0.7
+)
+
+type sitemapIndex struct {
+	XMLName  xml.Name          `xml:"http://www.sitemaps.org/schemas/sitemap/0.9 sitemapindex"`
+	Sitemaps []*sitemapPointer `xml:"sitemap"`
+}
+
+type sitemapPointer struct {
+	Loc     string `xml:"loc"`
+	LastMod string `xml:"lastmod,omitempty"`
+}
+
+type urlSet struct {
+	XMLName    xml.Name      `xml:"http://www.sitemaps.org/schemas/sitemap/0.9 urlset"`
+	XMLNSImage string        `xml:"xmlns:image,attr"`
+	URLs       []*sitemapURL `xml:"url"`
+}
+
+type sitemapURL struct {
+	Loc      string          `xml:"loc"`
+	LastMod  string          `xml:"lastmod,omitempty"`
+	Priority string          `xml:"priority"`
+	Images   []*sitemapImage `xml:"image:image"`
+}
+
+type sitemapImage struct {
+	Loc   string `xml:"image:loc"`
+	Title string `xml:"image:title,omitempty"`
+}
+
+// Sitemaps writes a sitemap per section, one for the home and list pages, the sitemap.xml index
+// referencing them and a robots.txt pointing to the index. websiteRoot makes the urls absolute.
+func Sitemaps(sections []*Section, dataDirectory, websiteRoot, outputDirectory string) error {
+	websiteRoot = strings.TrimSuffix(websiteRoot, "/")
+	index := &sitemapIndex{}
+	write := func(name string, urls []*sitemapURL) error {
+		for part := 0; part*sitemapMaxURLs < len(urls); part++ {
+			filename := fmt.Sprintf("%s-sitemap.xml", name)
+			if part > 0 {
+				filename = fmt.Sprintf("%s-sitemap%d.xml", name, part+1)
+			}
+			end := (part + 1) * sitemapMaxURLs
+			if end > len(urls) {
+				end = len(urls)
+			}
+			chunk := urls[part*sitemapMaxURLs : end]
+			if errWrite := writeXML(filepath.Join(outputDirectory, filename), &urlSet{
+				XMLNSImage: "http://www.google.com/schemas/sitemap-image/1.1",
+				URLs:       chunk,
+			}); errWrite != nil {
+				return errWrite
+			}
+			index.Sitemaps = append(index.Sitemaps, &sitemapPointer{
+				Loc:     fmt.Sprintf("%s/%s", websiteRoot, filename),
+				LastMod: latest(chunk),
+			})
+		}
+		return nil
+	}
+	commits, errCommits := commitDates(dataDirectory)
+	if errCommits != nil {
+		return errCommits
+	}
+	pages := []*sitemapURL{{Loc: websiteRoot + "/", Priority: formatPriority(priority_Home)}}
+	for _, section := range sections {
+		urls := make([]*sitemapURL, 0, len(section.Pages))
+		// extra status effects, whose slug ends with -2, share the page of the effect
+		links := map[string]bool{}
+		for _, p := range section.Pages {
+			if p.GetWebsiteLink() == "" || p.GetWebsiteLink() == "/" || links[p.GetWebsiteLink()] {
+				continue
+			}
+			links[p.GetWebsiteLink()] = true
+			url := &sitemapURL{
+				Loc:      websiteRoot + p.GetWebsiteLink(),
+				LastMod:  lastMod(p, path.Join("docs", section.Directory, "current"), commits),
+				Priority: formatPriority(section.Priority),
+			}
+			if champion, ok := p.(*common.Champion); ok {
+				url.Images = append(url.Images, &sitemapImage{Loc: templatefuncs.ChampionThumbnail(champion.Slug), Title: champion.Name})
+			}
+			urls = append(urls, url)
+		}
+		pages = append(pages, &sitemapURL{Loc: websiteRoot + section.Link, LastMod: latest(urls), Priority: formatPriority(priority_List)})
+		if errWrite := write(section.Directory, urls); errWrite != nil {
+			return errWrite
+		}
+	}
+	pages[0].LastMod = latest(pages)
+	if errWrite := write("pages", pages); errWrite != nil {
+		return errWrite
+	}
+	if errWrite := writeXML(filepath.Join(outputDirectory, "sitemap.xml"), index); errWrite != nil {
+		return errWrite
+	}
+	robots := fmt.Sprintf("User-agent: *\nAllow: /\n\nSitemap: %s/sitemap.xml\n", websiteRoot)
+	return utils.WriteToFile(filepath.Join(outputDirectory, "robots.txt"), []byte(robots))
+}
+
+// lastMod is the most recent of the last commit of the entity file and of its date_added. The
+// modification time of files is not used, a checkout resets it.
+func lastMod(p paged.Paged, directory string, commits map[string]time.Time) string {
+	last, ok := commits[path.Join(directory, fmt.Sprintf("%s.json", p.GetPageSlug()))]
+	if !ok {
+		last = commits[path.Join(directory, "index.json")]
+	}
+	if added := parseDateAdded(dateAdded(p)); added.After(last) {
+		last = added
+	}
+	if last.IsZero() {
+		return ""
+	}
+	return last.UTC().Format(time.RFC3339)
+}
+
+// commitDates returns the date of the last commit of each file of docs/, by path relative to the
+// data directory. Data directories that are not git repositories have no dates.
+func commitDates(dataDirectory string) (map[string]time.Time, error) {
+	dates := map[string]time.Time{}
+	if _, errGit := exec.LookPath("git"); errGit != nil {
+		log.Println("git not found, sitemaps only use the dates entities were added")
+		return dates, nil
+	}
+	cmd := exec.Command("git", "-C", dataDirectory, "log", "--format=commit %cI", "--name-only", "--relative", "--", "docs")
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	output, errLog := cmd.Output()
+	if errLog != nil {
+		if strings.Contains(stderr.String(), "not a git repository") {
+			log.Printf("%s is not a git repository, sitemaps only use the dates entities were added\n", dataDirectory)
+			return dates, nil
+		}
+		return nil, fmt.Errorf("git log: %s %s", errLog, strings.TrimSpace(stderr.String()))
+	}
+	var date time.Time
+	for _, line := range strings.Split(string(output), "\n") {
+		if value := strings.TrimPrefix(line, "commit "); value != line {
+			var errDate error
+			if date, errDate = time.Parse(time.RFC3339, value); errDate != nil {
+				return nil, errDate
+			}
+		} else if line != "" {
+			// commits are listed from the most recent
+			if _, ok := dates[line]; !ok {
+				dates[line] = date
+			}
+		}
+	}
+	return dates, nil
+}
+
+func dateAdded(p paged.Paged) string {
+	switch entity := p.(type) {
+	case *common.Champion:
+		return entity.DateAdded
+	case *common.Faction:
+		return entity.DateAdded
+	case *common.StatusEffect:
+		return entity.DateAdded
+	case *common.Fusion:
+		return entity.DateAdded
+	}
+	return ""
+}
+
+func parseDateAdded(value string) time.Time {
+	for _, layout := range []string{time.RFC3339, "2006-01-02 15:04:05", "2006-01-02"} {
+		if t, errParse := time.Parse(layout, value); errParse == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// latest returns the most recent lastmod of urls, RFC3339 dates in UTC sort as strings
+func latest(urls []*sitemapURL) string {
+	last := ""
+	for _, url := range urls {
+		if url.LastMod > last {
+			last = url.LastMod
+		}
+	}
+	return last
+}
+
+func formatPriority(priority float64) string {
+	return fmt.Sprintf("%.1f", priority)
+}
+
+func writeXML(filename string, v interface{}) error {
+	data, errXML := xml.MarshalIndent(v, "", "  ")
+	if errXML != nil {
+		return errXML
+	}
+	return utils.WriteToFile(filename, append([]byte(xml.Header), data...))
+}
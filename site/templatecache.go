@@ -0,0 +1,87 @@
+package site
+
+import (
+	"fmt"
+	"html/template"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// TemplateCache keeps the parsed templates of folders and files until one of their files changes,
+// so a server parses templates again only after they are edited
+type TemplateCache struct {
+	funcMap template.FuncMap
+
+	mu      sync.Mutex
+	entries map[string]*cachedTemplate
+}
+
+type cachedTemplate struct {
+	version string
+	tmpl    *template.Template
+}
+
+// NewTemplateCache returns a cache parsing templates with funcMap
+func NewTemplateCache(funcMap template.FuncMap) *TemplateCache {
+	return &TemplateCache{funcMap: funcMap, entries: map[string]*cachedTemplate{}}
+}
+
+// Folder returns the templates of dir, main.html is the entry point
+func (tc *TemplateCache) Folder(dir string) (*template.Template, error) {
+	return tc.get(dir, func() (*template.Template, error) {
+		return loadTemplates(dir, tc.funcMap)
+	})
+}
+
+// File returns the template of filename
+func (tc *TemplateCache) File(filename string) (*template.Template, error) {
+	return tc.get(filename, func() (*template.Template, error) {
+		return template.New(filepath.Base(filename)).Funcs(tc.funcMap).ParseFiles(filename)
+	})
+}
+
+func (tc *TemplateCache) get(path string, parse func() (*template.Template, error)) (*template.Template, error) {
+	version, errVersion := templateVersion(path)
+	if errVersion != nil {
+		return nil, errVersion
+	}
+	tc.mu.Lock()
+	entry := tc.entries[path]
+	tc.mu.Unlock()
+	if entry != nil && entry.version == version {
+		return entry.tmpl, nil
+	}
+	tmpl, errParse := parse()
+	if errParse != nil {
+		return nil, errParse
+	}
+	tc.mu.Lock()
+	tc.entries[path] = &cachedTemplate{version: version, tmpl: tmpl}
+	tc.mu.Unlock()
+	return tmpl, nil
+}
+
+// templateVersion describes the files of path, a folder or a file, by name, size and modification
+// time: it changes when a file is edited, added or removed
+func templateVersion(path string) (string, error) {
+	info, errStat := os.Stat(path)
+	if errStat != nil {
+		return "", errStat
+	}
+	files := []os.FileInfo{info}
+	if info.IsDir() {
+		var errFiles error
+		files, errFiles = ioutil.ReadDir(path)
+		if errFiles != nil {
+			return "", errFiles
+		}
+	}
+	parts := make([]string, len(files))
+	for idx, file := range files {
+		parts[idx] = fmt.Sprintf("%s:%d:%d", file.Name(), file.Size(), file.ModTime().UnixNano())
+	}
+	return strings.Join(parts, "|"), nil
+}
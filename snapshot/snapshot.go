@@ -0,0 +1,115 @@
+package snapshot
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/raid-codex/tools/common"
+)
+
+// Current is the version the tools read and write, snapshots are frozen copies of it
+const Current = "current"
+
+// VersionFormat is the layout of the dated versions created by Create
+const VersionFormat = "2006-01-02"
+
+var (
+	// Directories lists the folders of docs/ that are versioned
+	Directories = []string{"champions", "factions", "status-effects", "fusions", "masteries"}
+)
+
+// Create copies docs/<directory>/current to docs/<directory>/<version> for every versioned directory
+func Create(dataDirectory, version string) error {
+	if version == Current {
+		return fmt.Errorf("cannot snapshot %s onto itself", Current)
+	} else if _, errParse := time.Parse(VersionFormat, version); errParse != nil {
+		return errors.Annotatef(errParse, "version must be a date (%s)", VersionFormat)
+	}
+	for _, directory := range Directories {
+		target := filepath.Join(dataDirectory, "docs", directory, version)
+		if _, errStat := os.Stat(target); errStat == nil {
+			return errors.AlreadyExistsf("version %s of %s", version, directory)
+		}
+	}
+	for _, directory := range Directories {
+		source := filepath.Join(dataDirectory, "docs", directory, Current)
+		target := filepath.Join(dataDirectory, "docs", directory, version)
+		if errCopy := copyDirectory(source, target); errCopy != nil {
+			return errors.Annotatef(errCopy, "cannot snapshot %s", directory)
+		}
+	}
+	return nil
+}
+
+func copyDirectory(source, target string) error {
+	files, errDir := ioutil.ReadDir(source)
+	if errDir != nil {
+		return errDir
+	}
+	if errMkdir := os.MkdirAll(target, 0755); errMkdir != nil {
+		return errMkdir
+	}
+	for _, file := range files {
+		if file.IsDir() {
+			continue
+		}
+		content, errRead := ioutil.ReadFile(filepath.Join(source, file.Name()))
+		if errRead != nil {
+			return errRead
+		}
+		if errWrite := ioutil.WriteFile(filepath.Join(target, file.Name()), content, file.Mode()); errWrite != nil {
+			return errWrite
+		}
+	}
+	return nil
+}
+
+// Versions returns the snapshots of the data directory, oldest first. Current is not included.
+func Versions(dataDirectory string) ([]string, error) {
+	entries, errDir := ioutil.ReadDir(filepath.Join(dataDirectory, "docs", "champions"))
+	if errDir != nil {
+		return nil, errDir
+	}
+	versions := make([]string, 0)
+	for _, entry := range entries {
+		if !entry.IsDir() || entry.Name() == Current {
+			continue
+		}
+		if _, errParse := time.Parse(VersionFormat, entry.Name()); errParse != nil {
+			continue
+		}
+		versions = append(versions, entry.Name())
+	}
+	sort.Strings(versions)
+	return versions, nil
+}
+
+// Latest returns the most recent snapshot, or "" when there is none
+func Latest(dataDirectory string) (string, error) {
+	versions, errVersions := Versions(dataDirectory)
+	if errVersions != nil || len(versions) == 0 {
+		return "", errVersions
+	}
+	return versions[len(versions)-1], nil
+}
+
+// Champions reads the champion index of a version
+func Champions(dataDirectory, version string) (common.ChampionList, error) {
+	filename := filepath.Join(dataDirectory, "docs", "champions", version, "index.json")
+	file, errOpen := os.Open(filename)
+	if errOpen != nil {
+		return nil, errOpen
+	}
+	defer file.Close()
+	var champions common.ChampionList
+	if errJSON := json.NewDecoder(file).Decode(&champions); errJSON != nil {
+		return nil, errors.Annotatef(errJSON, "cannot read %s", filename)
+	}
+	return champions, nil
+}
@@ -7,7 +7,7 @@ import (
 	"strings"
 
 	"github.com/raid-codex/tools/common"
-	"github.com/raid-codex/tools/utils"
+	"github.com/raid-codex/tools/media"
 )
 
 var (
@@ -45,12 +45,10 @@ var (
 			}
 			return champions
 		},
-		"championImage": func(slug string) string {
-			return fmt.Sprintf("%s/wp-content/uploads/champions/image-champion-%s.jpg", rootUrl, slug)
-		},
-		"championThumbnail": func(slug string) string {
-			return fmt.Sprintf("%s/wp-content/uploads/champion-thumbnails/image-champion-small-%s.jpg", rootUrl, slug)
-		},
+		"championImage":      ChampionImage,
+		"championThumbnail":  ChampionThumbnail,
+		"championSrcset":     ChampionSrcset,
+		"championWebPSrcset": ChampionWebPSrcset,
 		"championThumbnailFallback": func(slug string) string {
 			champions, _ := common.GetChampions(func(champion *common.Champion) bool {
 				return champion.Slug == slug
@@ -58,9 +56,9 @@ var (
 			if len(champions) != 1 {
 				panic(champions)
 			}
-			img, err := utils.ImageFallback(
+			img, err := resolveImage(
 				fmt.Sprintf("%s/wp-content/uploads/hashed-img/%s.png", rootUrl, champions[0].Thumbnail),
-				fmt.Sprintf("%s/wp-content/uploads/champion-thumbnails/image-champion-small-%s.jpg", rootUrl, slug),
+				ChampionThumbnail(slug),
 				blankImage,
 			)
 			if err != nil {
@@ -78,10 +76,10 @@ var (
 			if len(champions) != 1 {
 				panic(fmt.Sprintf("found champions: %+v for slug %s", champions, slug))
 			}
-			img, err := utils.ImageFallback(
-				fmt.Sprintf("%s/wp-content/uploads/champions/image-champion-%s.jpg", rootUrl, slug),
+			img, err := resolveImage(
+				ChampionImage(slug),
 				fmt.Sprintf("%s/wp-content/uploads/hashed-img/%s.png", rootUrl, champions[0].Thumbnail),
-				fmt.Sprintf("%s/wp-content/uploads/champion-thumbnails/image-champion-small-%s.jpg", rootUrl, slug),
+				ChampionThumbnail(slug),
 				blankImage,
 			)
 			if err != nil {
@@ -90,8 +88,8 @@ var (
 			return img
 		},
 		"skillImageFallback": func(slug string) string {
-			img, err := utils.ImageFallback(
-				fmt.Sprintf("%s/wp-content/uploads/hashed-img/%s.png", rootUrl, slug),
+			img, err := resolveImage(
+				uploadsURL(common.SkillImagePath(slug)),
 				blankImage,
 			)
 			if err != nil {
@@ -106,8 +104,8 @@ var (
 			return template.URL(s)
 		},
 		"effectImage": func(se *common.StatusEffect) template.HTML {
-			img, err := utils.ImageFallback(
-				fmt.Sprintf("%s/wp-content/uploads/status-effects/%s.png", rootUrl, se.ImageSlug),
+			img, err := resolveImage(
+				uploadsURL(common.StatusEffectImagePath(se.ImageSlug)),
 				blankImage,
 			)
 			if err != nil {
@@ -118,8 +116,8 @@ var (
 			))
 		},
 		"factionImage": func(faction *common.Faction) template.HTML {
-			img, err := utils.ImageFallback(
-				fmt.Sprintf("%s/wp-content/uploads/factions/%s.png", rootUrl, faction.ImageSlug),
+			img, err := resolveImage(
+				uploadsURL(common.FactionImagePath(faction.ImageSlug)),
 				blankImage,
 			)
 			if err != nil {
@@ -264,3 +262,83 @@ func gradeTitle(grade string) string {
 	}
 	return ""
 }
+
+// WithWebsiteRoot returns a copy of FuncMap where websiteLink points to root instead of the
+// WordPress site, an empty root keeps links relative to the host
+func WithWebsiteRoot(root string) template.FuncMap {
+	funcMap := template.FuncMap{}
+	for name, fn := range FuncMap {
+		funcMap[name] = fn
+	}
+	funcMap["websiteLink"] = func(websiteLink string) string {
+		return fmt.Sprintf("%s%s", strings.TrimSuffix(root, "/"), websiteLink)
+	}
+	return funcMap
+}
+
+// ChampionImage returns the url of the full image of a champion
+func ChampionImage(slug string) string {
+	return uploadsURL(common.ChampionImagePath(slug))
+}
+
+// ChampionThumbnail returns the url of the small image of a champion
+func ChampionThumbnail(slug string) string {
+	return uploadsURL(media.ChampionSmallImagePath(slug))
+}
+
+// ChampionSrcset returns the srcset of the full image of a champion, with the resized images
+// generated by website media generate. The images and their width are read from the manifest, the
+// srcset is empty without one or when the champion has no image.
+func ChampionSrcset(slug string) string {
+	return championSrcset(slug, func(p string) string { return p })
+}
+
+// ChampionWebPSrcset is ChampionSrcset for the WebP variants of the images
+func ChampionWebPSrcset(slug string) string {
+	return championSrcset(slug, media.WebPPath)
+}
+
+// championSrcset lists the variants of the portrait and of its resized images in the manifest, with
+// the width of the image they are a variant of as WebP images have no width in the manifest
+func championSrcset(slug string, variant func(string) string) string {
+	manifest := Images().Manifest()
+	portrait := common.ChampionImagePath(slug)
+	if manifest == nil || !manifest.Has(variant(portrait)) {
+		return ""
+	}
+	paths := make([]string, 0, len(media.ChampionImageWidths)+1)
+	for _, width := range media.ChampionImageWidths {
+		paths = append(paths, media.SizedPath(portrait, width))
+	}
+	paths = append(paths, portrait)
+	candidates := make([]string, 0, len(paths))
+	for _, p := range paths {
+		if width := manifest.Width(p); width > 0 && manifest.Has(variant(p)) {
+			candidates = append(candidates, fmt.Sprintf("%s %dw", uploadsURL(variant(p)), width))
+		}
+	}
+	return strings.Join(candidates, ", ")
+}
+
+func uploadsURL(p string) string {
+	return fmt.Sprintf("%s%s%s", rootUrl, media.UploadsMarker, p)
+}
+
+// images is the resolver of the image fallbacks, it picks the preferred image without checking it
+// until UseImages is called
+var images, _ = media.NewResolver(nil, false, "")
+
+// Images returns the resolver of the image fallbacks
+func Images() *media.Resolver {
+	return images
+}
+
+// UseImages sets the resolver of the image fallbacks, before any template is rendered. Commands
+// build it with media.ResolverFromEnv.
+func UseImages(resolver *media.Resolver) {
+	images = resolver
+}
+
+func resolveImage(urls ...string) (string, error) {
+	return Images().Resolve(urls...)
+}
@@ -0,0 +1,172 @@
+package scrapcheck
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"encoding/csv"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/juju/errors"
+)
+
+// Expectation is a structural assumption a scraper makes about a page.
+// Selector must match at least MinCount elements (1 when unset). When Equals, Contains, HasSuffix
+// or Regexp are set, the text of at least one match must satisfy them. When Texts is set, the
+// trimmed texts of the matches must include them in that order, e.g. the headers of a table.
+type Expectation struct {
+	Description string
+	Selector    string
+	MinCount    int
+	Equals      string
+	Contains    string
+	HasSuffix   string
+	Regexp      *regexp.Regexp
+	Texts       []string
+}
+
+// Check gathers the expectations of a scraper on a page.
+// Sheets exported by hand are checked with Header instead: the content is read as CSV separated
+// by Delimiter (',' when unset) and Header, the function the parser validates the first line
+// with, must accept its first line. URL may then be a local file.
+type Check struct {
+	Scraper      string
+	URL          string
+	Expectations []Expectation
+	Header       func(line []string) error
+	Delimiter    rune
+}
+
+type Failure struct {
+	Expectation Expectation
+	Reason      string
+}
+
+// Fetcher retrieves pages, keeping them in CacheDirectory when set.
+// Cached pages are used unless Fresh is set.
+type Fetcher struct {
+	CacheDirectory string
+	Fresh          bool
+}
+
+func (f *Fetcher) cacheFile(url string) string {
+	return filepath.Join(f.CacheDirectory, fmt.Sprintf("%x.html", sha1.Sum([]byte(url))))
+}
+
+func (f *Fetcher) Fetch(url string) ([]byte, error) {
+	if !strings.HasPrefix(url, "http://") && !strings.HasPrefix(url, "https://") {
+		return ioutil.ReadFile(url)
+	}
+	if f.CacheDirectory != "" && !f.Fresh {
+		content, errRead := ioutil.ReadFile(f.cacheFile(url))
+		if errRead == nil {
+			return content, nil
+		} else if !os.IsNotExist(errRead) {
+			return nil, errRead
+		}
+	}
+	resp, errResponse := http.Get(url)
+	if errResponse != nil {
+		return nil, errResponse
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != 200 {
+		return nil, fmt.Errorf("request %s returned %d", url, resp.StatusCode)
+	}
+	content, errRead := ioutil.ReadAll(resp.Body)
+	if errRead != nil {
+		return nil, errRead
+	}
+	if f.CacheDirectory != "" {
+		if errMkdir := os.MkdirAll(f.CacheDirectory, 0755); errMkdir != nil {
+			return nil, errMkdir
+		}
+		if errWrite := ioutil.WriteFile(f.cacheFile(url), content, 0644); errWrite != nil {
+			return nil, errWrite
+		}
+	}
+	return content, nil
+}
+
+// Run fetches the page of the check and returns the expectations it does not meet
+func (c *Check) Run(fetcher *Fetcher) ([]*Failure, error) {
+	content, errFetch := fetcher.Fetch(c.URL)
+	if errFetch != nil {
+		return nil, errors.Annotatef(errFetch, "cannot fetch %s", c.URL)
+	}
+	if c.Header != nil {
+		return c.runSheet(content)
+	}
+	doc, errDoc := goquery.NewDocumentFromReader(bytes.NewReader(content))
+	if errDoc != nil {
+		return nil, errDoc
+	}
+	failures := make([]*Failure, 0)
+	for _, expectation := range c.Expectations {
+		if reason := expectation.verify(doc); reason != "" {
+			failures = append(failures, &Failure{Expectation: expectation, Reason: reason})
+		}
+	}
+	return failures, nil
+}
+
+func (c *Check) runSheet(content []byte) ([]*Failure, error) {
+	reader := csv.NewReader(bytes.NewReader(content))
+	if c.Delimiter != 0 {
+		reader.Comma = c.Delimiter
+	}
+	reader.FieldsPerRecord = -1
+	line, errRead := reader.Read()
+	if errRead != nil {
+		return nil, errors.Annotatef(errRead, "cannot read the first line of %s", c.URL)
+	}
+	if errHeader := c.Header(line); errHeader != nil {
+		return []*Failure{{Expectation: Expectation{Description: "header"}, Reason: errHeader.Error()}}, nil
+	}
+	return []*Failure{}, nil
+}
+
+func (e Expectation) verify(doc *goquery.Document) string {
+	sel := doc.Find(e.Selector)
+	minCount := e.MinCount
+	if minCount == 0 {
+		minCount = 1
+	}
+	if sel.Length() < minCount {
+		return fmt.Sprintf("selector '%s' matched %d elements, expected at least %d", e.Selector, sel.Length(), minCount)
+	}
+	if len(e.Texts) > 0 {
+		next := 0
+		sel.EachWithBreak(func(_ int, s *goquery.Selection) bool {
+			if strings.TrimSpace(s.Text()) == e.Texts[next] {
+				next++
+			}
+			return next < len(e.Texts)
+		})
+		if next < len(e.Texts) {
+			return fmt.Sprintf("no element matching '%s' has the text '%s'", e.Selector, e.Texts[next])
+		}
+	}
+	if e.Equals == "" && e.Contains == "" && e.HasSuffix == "" && e.Regexp == nil {
+		return ""
+	}
+	found := false
+	sel.EachWithBreak(func(_ int, s *goquery.Selection) bool {
+		text := s.Text()
+		found = (e.Equals == "" || text == e.Equals) &&
+			(e.Contains == "" || strings.Contains(text, e.Contains)) &&
+			(e.HasSuffix == "" || strings.HasSuffix(text, e.HasSuffix)) &&
+			(e.Regexp == nil || e.Regexp.MatchString(strings.TrimSpace(text)))
+		return !found
+	})
+	if !found {
+		return fmt.Sprintf("no element matching '%s' has the expected text", e.Selector)
+	}
+	return ""
+}
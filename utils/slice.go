@@ -14,3 +14,12 @@ func UniqueSlice(in []string, transforms ...func(string) string) []string {
 	}
 	return out
 }
+
+func InSlice(in []string, value string) bool {
+	for _, v := range in {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
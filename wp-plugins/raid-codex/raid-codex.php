@@ -7,6 +7,8 @@
 * Author URI: https://github.com/geoffreybauduin
 */
 
+require_once(__DIR__."/uploads.php");
+
 add_action('save_post', 'save_post_hook', 10, 3);
 
 function save_post_hook($post_ID, $post, $update) {
@@ -0,0 +1,68 @@
+<?php
+
+// The media library files uploads by month, the images the pages link to are stored at their exact
+// path under the uploads folder instead (see website media sync)
+add_action('rest_api_init', 'register_uploads_route');
+
+// the folders of the uploads the pages link to, nothing else can be written
+$raid_codex_upload_folders = array('champions', 'champion-thumbnails', 'hashed-img', 'factions', 'status-effects');
+
+function register_uploads_route()
+{
+    register_rest_route('raid-codex/v1', '/uploads', array(
+        'methods' => 'POST',
+        'callback' => 'upload_at_path',
+        'permission_callback' => function () {
+            return current_user_can('upload_files');
+        },
+    ));
+}
+
+function upload_at_path( $request )
+{
+    global $raid_codex_upload_folders;
+    $path = $request->get_param('path');
+    if (!$path || strpos($path, '..') !== false || substr($path, 0, 1) == '/')
+    {
+        return new WP_Error('invalid_path', "invalid path $path", array('status' => 400));
+    }
+    $folder = explode('/', $path)[0];
+    if (!in_array($folder, $raid_codex_upload_folders, true) || $folder == $path)
+    {
+        return new WP_Error('invalid_path', "$path is not in ".implode('/, ', $raid_codex_upload_folders).'/', array('status' => 400));
+    }
+    $uploads = wp_upload_dir();
+    $filename = $uploads['basedir'].'/'.$path;
+    // images are only replaced on purpose, e.g. a new portrait
+    if (file_exists($filename) && !rest_sanitize_boolean($request->get_param('overwrite')))
+    {
+        return new WP_Error('file_exists', "$path exists, set overwrite to replace it", array('status' => 409));
+    }
+    // the body is checked to be an image matching the extension before it is moved to path
+    require_once(ABSPATH.'wp-admin/includes/file.php');
+    $tmp = wp_tempnam(basename($path));
+    if (!$tmp || file_put_contents($tmp, $request->get_body()) === false)
+    {
+        return new WP_Error('cannot_write', "cannot write $path", array('status' => 500));
+    }
+    $filetype = wp_check_filetype_and_ext($tmp, basename($path));
+    if (!$filetype['type'] || substr($filetype['type'], 0, 6) != 'image/' || getimagesize($tmp) === false)
+    {
+        @unlink($tmp);
+        return new WP_Error('invalid_type', "$path is not an image", array('status' => 400));
+    }
+    if (!wp_mkdir_p(dirname($filename)))
+    {
+        @unlink($tmp);
+        return new WP_Error('cannot_create_folder', "cannot create the folder of $path", array('status' => 500));
+    }
+    if (!@rename($tmp, $filename))
+    {
+        @unlink($tmp);
+        return new WP_Error('cannot_write', "cannot write $path", array('status' => 500));
+    }
+    // same permissions as the uploads of the media library
+    $stat = stat(dirname($filename));
+    chmod($filename, $stat['mode'] & 0000666);
+    return array('path' => $path, 'url' => $uploads['baseurl'].'/'.$path);
+}